@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"bitbucket.org/christian_m/jiratool/internal/fakejira"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const e2eEnv = "JIRATOOL_E2E"
+
+// TestMainProcess runs main() with the arguments following "--" when the
+// test binary is started by runJiratool.
+func TestMainProcess(t *testing.T) {
+	if os.Getenv(e2eEnv) == "" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	os.Args = append([]string{"jiratool"}, args...)
+	main()
+	os.Exit(0)
+}
+
+func runJiratool(t *testing.T, serverURL string, args ...string) (string, error) {
+	t.Helper()
+	cmdArgs := append([]string{"-test.run=TestMainProcess", "--", "-u", "username", "-a", "apikey", "-url", serverURL, "-yes"}, args...)
+	cmd := exec.Command(os.Args[0], cmdArgs...)
+	cmd.Env = append(os.Environ(), e2eEnv+"=1", "HOME="+t.TempDir())
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func TestEndToEnd(t *testing.T) {
+	s := fakejira.NewServer()
+	if err := seedFakeServer(s); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	template := filepath.Join(t.TempDir(), "template.json")
+	err := os.WriteFile(template, []byte(`{"issues": [{"issueType": "Task", "summary": "Deploy {project} {version} am {releaseDate}",
+		"components": ["Backend"], "subTasks": [{"summary": "Smoke Test {version}"}]}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := runJiratool(t, ts.URL, "-p", "DEMO,SHOP", "-cv", "1.2.0", "-rd", "2021-06-01", "-it", template)
+	if err != nil {
+		t.Fatalf("create version: %v\n%s", err, out)
+	}
+	for _, key := range []string{"DEMO", "SHOP"} {
+		prj, _ := s.Project(key)
+		if len(prj.Versions) != 3 || prj.Versions[2].Name != "1.2.0" {
+			t.Errorf("got: %d versions in %s - want: version 1.2.0 created", len(prj.Versions), key)
+		}
+		deploy, ok := s.Issue(key + "-5")
+		if !ok || deploy.Field("summary") != "Deploy "+key+" 1.2.0 am 2021-06-01" || deploy.Field("fixVersions") != "1.2.0" || deploy.Field("components") != "Backend" {
+			t.Errorf("got: %+v - want: deploy issue of 1.2.0 in %s\n%s", deploy, key, out)
+		}
+		if smoke, _ := s.Issue(key + "-6"); smoke.Field("parent") != key+"-5" {
+			t.Errorf("got: %+v - want: sub-task of %s-5", smoke, key)
+		}
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-rv", "1.1.0")
+	if err != nil {
+		t.Fatalf("release version: %v\n%s", err, out)
+	}
+	if prj, _ := s.Project("DEMO"); prj.Versions[1].Released {
+		t.Errorf("got: version 1.1.0 with unresolved issues released - want: not released\n%s", out)
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO,SHOP", "-rv", "1.2.0", "-rd", "2021-06-01", "-force",
+		"-cmt", "Ausgeliefert in **{version}** am {releaseDate}", "-cmv", "role:Developers")
+	if err != nil {
+		t.Fatalf("release version with comment: %v\n%s", err, out)
+	}
+	for _, key := range []string{"DEMO-5", "DEMO-6", "SHOP-5"} {
+		comments, _ := s.Comments(key)
+		if len(comments) != 1 || adf.ToText(comments[0].Body) != "Ausgeliefert in 1.2.0 am 2021-06-01" || comments[0].Visibility == nil {
+			t.Errorf("got: %+v - want: release comment on %s\n%s", comments, key, out)
+		}
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO,SHOP", "-vi", "1.0.0", "-la", "released-1.0.0", "-o", "csv")
+	if err != nil {
+		t.Fatalf("add labels: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "DEMO-1,,released-1.0.0") || !strings.Contains(out, "SHOP-1,,released-1.0.0") {
+		t.Errorf("got: %q - want: report of DEMO-1 and SHOP-1", out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-js", "labels = released-1.0.0", "-lr", "released-1.0.0=shipped-1.0.0")
+	if err != nil {
+		t.Fatalf("rename labels: %v\n%s", err, out)
+	}
+	if i, _ := s.Issue("DEMO-1"); i.Field("labels") != "shipped-1.0.0" {
+		t.Errorf("got: labels %q - want: shipped-1.0.0", i.Field("labels"))
+	}
+	if i, _ := s.Issue("SHOP-1"); i.Field("labels") != "released-1.0.0" {
+		t.Errorf("got: labels %q - want: SHOP-1 unchanged", i.Field("labels"))
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "SHOP", "-vi", "1.1.0", "-lk", "DEMO-1")
+	if err != nil {
+		t.Fatalf("link issues: %v\n%s", err, out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "SHOP", "-vi", "1.1.0", "-lk", "DEMO-1", "-lkt", "is blocked by")
+	if err != nil || strings.Count(out, "bereits verknüpft") != 3 {
+		t.Errorf("got: %v - want: links kept\n%s", err, out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-ik", "DEMO-1", "-lg", "mermaid")
+	if err != nil {
+		t.Fatalf("link graph: %v\n%s", err, out)
+	}
+	for _, want := range []string{`DEMO_1["DEMO-1: Anmeldung"]`, "SHOP_2 -->|blocks| DEMO_1", "SHOP_4 -->|blocks| DEMO_1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got: %s - want: %s", out, want)
+		}
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-vi", "1.1.0", "-tr", "Ready for Release")
+	if err == nil {
+		t.Errorf("got: no error - want: DEMO-4 in To Do not transitioned\n%s", out)
+	}
+	if i, _ := s.Issue("DEMO-3"); i.Field("status") != fakejira.StatusReady {
+		t.Errorf("got: status %q - want: %s\n%s", i.Field("status"), fakejira.StatusReady, out)
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-js", "project = DEMO AND resolution = Unresolved", "-o", "csv")
+	if err != nil {
+		t.Fatalf("search: %v\n%s", err, out)
+	}
+	for _, want := range []string{"DEMO-3", "DEMO-4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got: %q - want: %s in search result", out, want)
+		}
+	}
+	if strings.Contains(out, "DEMO-1,") {
+		t.Errorf("got: %q - want: no resolved issue DEMO-1", out)
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO,SHOP", "-cmp")
+	if err != nil {
+		t.Errorf("compare: %v\n%s", err, out)
+	}
+
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO,SHOP", "-vr", "open", "-o", "csv")
+	if err != nil {
+		t.Fatalf("version report: %v\n%s", err, out)
+	}
+	for _, want := range []string{"DEMO,1.1.0,offen 2021-04-01,3,1,1,1,33,15,5,", "SHOP,1.1.0,offen 2021-04-01,3,1,1,1,33,15,5,"} {
+		if !strings.Contains(out, want) || !strings.Contains(out, ",ja\n") {
+			t.Errorf("got: %q - want: overdue %s", out, want)
+		}
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-vr", "1.1.0", "-o", "html")
+	if err != nil {
+		t.Fatalf("version report: %v\n%s", err, out)
+	}
+	for _, want := range []string{"<h2>DEMO 1.1.0 <span class=\"overdue\">überfällig</span></h2>", `<polyline class="actual"`, `<polyline class="ideal"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got: %s - want: %s", out, want)
+		}
+	}
+
+	traceFile := filepath.Join(t.TempDir(), "trace.log")
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-iv", "1.0.0", "-tf", traceFile)
+	if err != nil {
+		t.Fatalf("inspect version: %v\n%s", err, out)
+	}
+	trace, err := os.ReadFile(traceFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(trace), "--> GET "+ts.URL+"/rest/api/3/project/DEMO") || strings.Contains(string(trace), "apikey") {
+		t.Errorf("got: %s - want: traced request without API key", trace)
+	}
+}
+
+func TestEndToEndReleaseTransition(t *testing.T) {
+	s := fakejira.NewServer()
+	if err := seedFakeServer(s); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	for _, status := range []string{fakejira.StatusInProgress, fakejira.StatusReady} {
+		out, err := runJiratool(t, ts.URL, "-p", "DEMO", "-ik", "DEMO-3,DEMO-4", "-tr", status)
+		if err != nil {
+			t.Fatalf("transition to %s: %v\n%s", status, err, out)
+		}
+	}
+	out, err := runJiratool(t, ts.URL, "-p", "DEMO", "-rv", "1.1.0", "-tr", fakejira.StatusDone, "-trf", "resolution=Done")
+	if err != nil {
+		t.Fatalf("release version with transition: %v\n%s", err, out)
+	}
+	if prj, _ := s.Project("DEMO"); !prj.Versions[1].Released {
+		t.Errorf("got: version 1.1.0 not released - want: released after the transition\n%s", out)
+	}
+	for _, key := range []string{"DEMO-3", "DEMO-4"} {
+		if i, _ := s.Issue(key); i.Field("status") != fakejira.StatusDone {
+			t.Errorf("got: status %q of %s - want: %s\n%s", i.Field("status"), key, fakejira.StatusDone, out)
+		}
+	}
+}
+
+func TestEndToEndSprints(t *testing.T) {
+	s := fakejira.NewServer()
+	if err := seedFakeServer(s); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	out, err := runJiratool(t, ts.URL, "-p", "DEMO", "-ns", "Sprint 2", "-sfv")
+	if err != nil {
+		t.Fatalf("next sprint: %v\n%s", err, out)
+	}
+	if prj, _ := s.Project("DEMO"); prj.Versions[len(prj.Versions)-1].Name != "Sprint 2" {
+		t.Errorf("got: %v - want: fixVersion Sprint 2", prj.Versions)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-cs", "Sprint 1")
+	if err != nil {
+		t.Fatalf("close sprint: %v\n%s", err, out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-ss", "Sprint 2")
+	if err != nil {
+		t.Fatalf("start sprint: %v\n%s", err, out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-lb", "-o", "csv")
+	if err != nil {
+		t.Fatalf("list boards: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "DEMO,DEMO Board,Sprint 2,active,") || strings.Contains(out, "Sprint 1") {
+		t.Errorf("got: %s - want: only Sprint 2 active", out)
+	}
+	out, err = runJiratool(t, ts.URL, "-p", "DEMO", "-js", "sprint = \"Sprint 2\" ORDER BY key", "-f", "summary", "-o", "csv")
+	if err != nil {
+		t.Fatalf("search: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "DEMO-3,Warenkorb\nDEMO-4,Export\n") || strings.Contains(out, "DEMO-2") {
+		t.Errorf("got: %s - want: DEMO-3 and DEMO-4 moved to Sprint 2", out)
+	}
+}
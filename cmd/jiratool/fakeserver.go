@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"bitbucket.org/christian_m/jiratool/internal/fakejira"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func runFakeServer(args []string) error {
+	fs := flag.NewFlagSet("fake-server", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "Adresse des Servers")
+	username := fs.String("u", "", "Jira Username (leer: keine Anmeldung nötig)")
+	apiKey := fs.String("a", "", "Jira API-Key")
+	empty := fs.Bool("empty", false, "Ohne Demodaten starten")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s := fakejira.NewServer()
+	s.Username = *username
+	s.Token = *apiKey
+	if !*empty {
+		if err := seedFakeServer(s); err != nil {
+			return err
+		}
+	}
+	log.Printf("Jira Testserver läuft auf http://%s (jiratool -url http://%s ...)", *addr, *addr)
+	return http.ListenAndServe(*addr, s)
+}
+
+func seedFakeServer(s *fakejira.Server) error {
+	releaseDate := "2021-03-01"
+	nextStart, nextRelease := "2021-03-01", "2021-04-01"
+	for _, prj := range []struct{ key, name string }{{"DEMO", "Demo Projekt"}, {"SHOP", "Shop"}} {
+		s.AddProject(prj.key, prj.name)
+		if _, err := s.AddVersion(prj.key, internal.Version{Name: "1.0.0", Released: true, ReleaseDate: &releaseDate}); err != nil {
+			return err
+		}
+		if _, err := s.AddVersion(prj.key, internal.Version{Name: "1.1.0", StartDate: &nextStart, ReleaseDate: &nextRelease}); err != nil {
+			return err
+		}
+		if _, err := s.AddComponent(prj.key, internal.Component{Name: "Backend", AssigneeType: "PROJECT_DEFAULT"}); err != nil {
+			return err
+		}
+		boardId, err := s.AddBoard(prj.key, prj.key+" Board", "scrum")
+		if err != nil {
+			return err
+		}
+		start, end := "2021-03-01T09:00:00.000+01:00", "2021-03-15T09:00:00.000+01:00"
+		sprintId, err := s.AddSprint(internal.Sprint{Name: "Sprint 1", State: internal.SprintActive, StartDate: &start, EndDate: &end, OriginBoardId: boardId})
+		if err != nil {
+			return err
+		}
+		for _, i := range []struct {
+			summary, status, fixVersion string
+			sprint                      bool
+			points                      float64
+			created, resolved           string
+		}{
+			{"Anmeldung", fakejira.StatusDone, "1.0.0", false, 3, "2021-02-01", "2021-02-20"},
+			{"Suche", fakejira.StatusDone, "1.1.0", true, 5, "2021-03-01", "2021-03-10"},
+			{"Warenkorb", fakejira.StatusInProgress, "1.1.0", true, 8, "2021-03-01", ""},
+			{"Export", fakejira.StatusToDo, "1.1.0", true, 2, "2021-03-05", ""},
+		} {
+			key, err := s.AddIssue(prj.key, "Story", i.summary, i.status, i.fixVersion)
+			if err != nil {
+				return err
+			}
+			if err := s.SetIssueField(key, "customfield_10016", i.points); err != nil {
+				return err
+			}
+			created, _ := time.Parse(layoutISO, i.created)
+			resolved, _ := time.Parse(layoutISO, i.resolved)
+			if err := s.SetIssueDates(key, created.Add(9*time.Hour), resolved.Add(17*time.Hour)); err != nil {
+				return err
+			}
+			if i.sprint {
+				if err := s.SetIssueSprint(key, sprintId); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -4,31 +4,134 @@ import (
 	"bitbucket.org/christian_m/jiratool/internal"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
 const (
-	layoutISO = "2006-01-02"
+	layoutISO   = "2006-01-02"
+	configFile  = ".jiratool.json"
+	journalFile = ".jiratool-journal.jsonl"
+	auditFile   = ".jiratool-audit.jsonl"
 )
 
 var (
 	flagUsername       = flag.String("u", "", "Jira Username")
 	flagApiKey         = flag.String("a", "", "Jira API-Key")
 	flagCloudAlias     = flag.String("h", "", "Jira Cloud Alias")
-	flagProjects       = flag.String("p", "", "Jira Projekte (kommasepariert)")
+	flagURL            = flag.String("url", "", "Jira URL statt Cloud Alias (z.B. http://localhost:8080)")
+	flagConfig         = flag.String("c", "", "Konfigurationsdatei (Standard: ~/.jiratool.json)")
+	flagProjects       = flag.String("p", "", "Jira Projekte oder Projektgruppen (kommasepariert)")
 	flagInspectVersion = flag.String("iv", "", "Projektversion anzeigen")
 	flagCreateVersion  = flag.String("cv", "", "Projektversion anlegen")
+	flagIssueTemplate  = flag.String("it", "", "Vorgänge aus dieser Vorlage zur neuen Projektversion anlegen (mit -cv)")
 	flagReleaseVersion = flag.String("rv", "", "Projektversion Release")
 	flagReleaseDate    = flag.String("rd", "", "Projektversion Release Datum")
+	flagCreateComp     = flag.String("cc", "", "Komponente anlegen")
+	flagUpdateComp     = flag.String("uc", "", "Komponente aktualisieren")
+	flagDeleteComp     = flag.String("dc", "", "Komponente löschen")
+	flagListComps      = flag.Bool("lc", false, "Komponenten anzeigen")
+	flagCompLead       = flag.String("cl", "", "Komponentenverantwortlicher (Account-Id)")
+	flagCompAssignee   = flag.String("ca", "", "Standardbearbeiter der Komponente (PROJECT_DEFAULT, COMPONENT_LEAD, PROJECT_LEAD, UNASSIGNED)")
+	flagCompMoveTo     = flag.String("cm", "", "Vorgänge der gelöschten Komponente in diese Komponente verschieben")
+	flagSyncVersions   = flag.String("sv", "", "Projektversionen aus diesem Vorlageprojekt übernehmen")
+	flagSyncArchive    = flag.Bool("sva", false, "Projektversionen, die im Vorlageprojekt fehlen, archivieren")
+	flagCompare        = flag.Bool("cmp", false, "Projektversionen der Projekte vergleichen")
+	flagVersionReport  = flag.String("vr", "", "Fortschritt dieser Projektversionen berichten (kommasepariert, open für alle offenen)")
+	flagPointsField    = flag.String("sp", "customfield_10016", "Feld mit Story Points oder Schätzung der Vorgänge (z.B. timeoriginalestimate)")
+	flagTUI            = flag.Bool("tui", false, "Interaktiver Modus (Projekte aus -p oder allen Projektgruppen)")
+	flagYes            = flag.Bool("yes", false, "Destruktive Änderungen ohne Rückfrage ausführen")
+	flagUndo           = flag.String("undo", "", "Änderungen eines Laufs rückgängig machen (Run-Id)")
+	flagListJournal    = flag.Bool("jl", false, "Journal der Änderungen anzeigen")
+	flagVerifyAudit    = flag.Bool("av", false, "Verkettung des Audit-Logs prüfen")
+	flagForce          = flag.Bool("force", false, "Projektversion trotz offener Vorgänge releasen")
+	flagSearch         = flag.String("js", "", "Vorgänge per JQL suchen")
+	flagAssignFixVer   = flag.String("afv", "", "fixVersion den Vorgängen zuordnen (wird bei Bedarf angelegt)")
+	flagSetFixVer      = flag.Bool("fvs", false, "fixVersions der Vorgänge ersetzen statt ergänzen")
+	flagIssueKeys      = flag.String("ik", "", "Vorgänge (kommasepariert, - liest von stdin)")
+	flagVersionIssues  = flag.String("vi", "", "Vorgänge mit dieser fixVersion")
+	flagTransition     = flag.String("tr", "", "Vorgänge in diesen Status überführen (mit -rv: offene Vorgänge der Version nach dem Release)")
+	flagTransFields    = flag.String("trf", "", "Pflichtfelder des Übergangs ohne Vorgabewert (Feld=Wert, kommasepariert)")
+	flagAddLabels      = flag.String("la", "", "Labels den Vorgängen hinzufügen (kommasepariert)")
+	flagRemoveLabels   = flag.String("ld", "", "Labels von den Vorgängen entfernen (kommasepariert)")
+	flagRenameLabels   = flag.String("lr", "", "Labels der Vorgänge umbenennen (alt=neu, kommasepariert)")
+	flagLinkIssue      = flag.String("lk", "", "Diesen Vorgang mit den Vorgängen verknüpfen (z.B. REL-1)")
+	flagLinkType       = flag.String("lkt", "is blocked by", "Art der Verknüpfung (Name, inward oder outward Beschreibung)")
+	flagLinkGraph      = flag.String("lg", "", "Verknüpfungen der Vorgänge als Graph ausgeben (dot, mermaid)")
+	flagComment        = flag.String("cmt", "", "Kommentar zu den Vorgängen (Markdown mit {project}, {version}, {releaseDate}, {issue}; mit -rv: Vorgänge der Version nach dem Release)")
+	flagCommentVis     = flag.String("cmv", "", "Sichtbarkeit des Kommentars (role:<Rolle> oder group:<Gruppe>)")
+	flagGitLog         = flag.String("gl", "", "Vorgänge aus Commits und Branches eines git Bereichs (z.B. v1.0..v1.1)")
+	flagGitDir         = flag.String("gd", ".", "git Repository")
+	flagFields         = flag.String("f", "summary,status,fixVersions", "Felder der Vorgänge (kommasepariert)")
+	flagOutput         = flag.String("o", internal.FormatTable, "Ausgabeformat (table, csv, json, markdown, html mit -vr)")
+	flagRecord         = flag.String("rec", "", "Jira Anfragen und Antworten in diese Aufnahme schreiben (ohne Zugangsdaten)")
+	flagProxy          = flag.String("proxy", "", "HTTP(S) Proxy (z.B. http://proxy:3128)")
+	flagCACert         = flag.String("cacert", "", "Zusätzliche CA Zertifikate (PEM)")
+	flagClientCert     = flag.String("cert", "", "Client-Zertifikat (PEM)")
+	flagClientKey      = flag.String("key", "", "Schlüssel des Client-Zertifikats (PEM)")
+	flagTrace          = flag.Bool("trace", false, "Jira Anfragen und Antworten auf stderr protokollieren (ohne Zugangsdaten)")
+	flagTraceFile      = flag.String("tf", "", "Jira Anfragen und Antworten in diese Datei protokollieren")
+	flagListBoards     = flag.Bool("lb", false, "Scrum Boards und offene Sprints der Projekte anzeigen")
+	flagNextSprint     = flag.String("ns", "", "Nächsten Sprint in den Scrum Boards der Projekte anlegen")
+	flagStartSprint    = flag.String("ss", "", "Sprint starten")
+	flagCloseSprint    = flag.String("cs", "", "Sprint abschließen, offene Vorgänge in den nächsten Sprint verschieben")
+	flagSprintLength   = flag.Int("sl", 14, "Sprintlänge in Tagen")
+	flagSprintVersion  = flag.Bool("sfv", false, "Gleichnamige fixVersion zum neuen Sprint anlegen")
+	flagCache          = flag.String("cache", "", "Jira Antworten so lange zwischenspeichern (z.B. 10m, 0 prüft immer per ETag)")
 )
 
 func main() {
 	flag.Parse()
+	if flag.Arg(0) == "fake-server" {
+		err := runFakeServer(flag.Args()[1:])
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	cfg, err := loadConfig(*flagConfig)
+	if err != nil {
+		fmt.Println(err)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	journal, err := openJournal(cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	audit, err := openAuditLog(cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *flagVerifyAudit {
+		records, err := audit.Verify()
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		log.Printf("Audit-Log %s ist unverändert (%d Einträge)", audit.Path, len(records))
+		return
+	}
+
+	if *flagListJournal {
+		err := listJournal(journal)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	u, err := createUserInfo(*flagUsername, *flagApiKey)
 	if err != nil {
 		fmt.Println(err)
@@ -36,24 +139,231 @@ func main() {
 		os.Exit(1)
 	}
 
-	if *flagCloudAlias == "" {
-		fmt.Println("Bitte den Jira Cloud Alias angeben:")
+	bu, err := baseURL(*flagCloudAlias, *flagURL)
+	if err != nil {
+		fmt.Println(err)
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
-	c, err := internal.CreateRestClient(u, &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.atlassian.net", *flagCloudAlias)})
+	jc, err := internal.CreateRestClient(u, bu)
 	if err != nil {
 		fmt.Println(err)
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
+	jc.HttpClient, err = transportConfig(cfg).HttpClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *flagTrace || *flagTraceFile != "" {
+		out, err := openTrace(*flagTraceFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		jc.HttpClient = &http.Client{Transport: &internal.Tracer{Transport: roundTripper(jc.HttpClient), Out: out}}
+	}
+	if writesJira() {
+		err = jc.EnableAudit(audit)
+	} else {
+		jc.Cache, err = openCache(cfg, *flagCache)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *flagRecord != "" {
+		rec, err := internal.NewRecorder(*flagRecord, internal.CassetteRecord)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		rec.Transport = roundTripper(jc.HttpClient)
+		rec.Scrub = []string{*flagUsername, *flagApiKey, bu.Host}
+		jc.HttpClient = &http.Client{Transport: rec}
+	}
+	c := &internal.JournalRestClient{RestClient: jc, Journal: journal, RunId: internal.NewRunId(), Warn: func(err error) { log.Println(err) }}
+	defer func() {
+		if c.Entries > 0 {
+			log.Printf("%d Änderungen im Journal unter Run-Id %s", c.Entries, c.RunId)
+		}
+	}()
+
+	if *flagUndo != "" {
+		err := undo(*flagUndo, journal, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	prjKeys, err := resolveProjects(*flagProjects)
+	projects := *flagProjects
+	if *flagTUI && projects == "" {
+		projects = strings.Join(cfg.GroupNames(), ",")
+	}
+	prjKeys, err := resolveProjects(projects, cfg)
 	if err != nil {
 		fmt.Println(err)
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
+
+	if *flagTUI {
+		t := &tui{in: stdin, out: os.Stdout, c: c, prjKeys: prjKeys}
+		t.run()
+		return
+	}
+
+	if *flagAssignFixVer != "" {
+		err := assignFixVersion(prjKeys, *flagAssignFixVer, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if (*flagTransition != "" || *flagComment != "") && *flagReleaseVersion == "" {
+		keys, err := collectIssueKeys(prjKeys, c)
+		if err == nil && *flagTransition != "" {
+			err = transitionIssues(keys, *flagTransition, c)
+		}
+		if err == nil && *flagComment != "" {
+			err = commentIssues(keys, *flagVersionIssues, c)
+		}
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagAddLabels != "" || *flagRemoveLabels != "" || *flagRenameLabels != "" {
+		err := editLabels(prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagLinkIssue != "" {
+		err := linkIssues(prjKeys, *flagLinkIssue, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagLinkGraph != "" {
+		err := linkGraph(prjKeys, *flagLinkGraph, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagGitLog != "" {
+		err := listGitIssues(prjKeys, *flagGitLog, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagSyncVersions != "" {
+		err := syncVersions(*flagSyncVersions, prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagCompare {
+		err := compareVersions(prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagVersionReport != "" {
+		err := reportVersions(prjKeys, *flagVersionReport, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagListComps {
+		err := listComponents(prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagListBoards {
+		err := listBoards(prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagNextSprint != "" || *flagStartSprint != "" || *flagCloseSprint != "" {
+		err := manageSprints(prjKeys, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagSearch != "" {
+		err := searchIssues(prjKeys, *flagSearch, c)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	relDate, err := releaseDate(*flagReleaseDate)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if _, err := internal.ParseVisibility(*flagCommentVis); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var template *internal.IssueTemplate
+	if *flagIssueTemplate != "" {
+		if *flagCreateVersion == "" {
+			fmt.Println("Vorlage -it nur zusammen mit -cv")
+			os.Exit(1)
+		}
+		template, err = internal.LoadIssueTemplate(*flagIssueTemplate)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	var prjs []*internal.Project
 	for _, pk := range prjKeys {
 		prj, err := c.GetProject(pk)
 		if err != nil {
@@ -65,6 +375,15 @@ func main() {
 			}
 			continue
 		}
+		prjs = append(prjs, prj)
+	}
+
+	if !confirmChanges(plannedChanges(prjs, relDate)) {
+		log.Println("Abgebrochen, keine Projekte geändert")
+		os.Exit(1)
+	}
+
+	for _, prj := range prjs {
 		switch {
 		case *flagInspectVersion != "":
 			ver := *flagInspectVersion
@@ -79,27 +398,65 @@ func main() {
 			err := internal.CreateVersion(prj, ver, c)
 			if err != nil {
 				log.Println(err)
-			} else {
-				log.Printf("Version %s in Projekt %s angelegt", ver, prj.Key)
+				break
+			}
+			log.Printf("Version %s in Projekt %s angelegt", ver, prj.Key)
+			if template != nil {
+				keys, err := internal.CreateTemplateIssues(prj, template, internal.TemplateValues{Version: ver, ReleaseDate: relDate}, c)
+				if len(keys) > 0 {
+					log.Printf("Vorgänge %s in Projekt %s angelegt", strings.Join(keys, ", "), prj.Key)
+				}
+				if err != nil {
+					log.Println(err)
+				}
 			}
 		case *flagReleaseVersion != "":
 			ver := *flagReleaseVersion
-			var relDate string
-			if *flagReleaseDate != "" {
-				relDate = *flagReleaseDate
-				_, err := time.Parse(layoutISO, relDate)
+			if *flagTransition != "" {
+				err := transitionVersionIssues(prj, ver, *flagTransition, c)
 				if err != nil {
-					log.Printf("Das Release Datum '%s' hat nicht das richtige Format (JJJJ-MM-TT)", relDate)
-					break
+					log.Println(err)
 				}
-			} else {
-				relDate = time.Now().Format(layoutISO)
+			}
+			if !*flagForce && !releaseReady(prj, ver, c) {
+				log.Printf("Version %s in Projekt %s nicht released", ver, prj.Key)
+				break
 			}
 			err = internal.ReleaseVersion(prj, ver, relDate, c)
+			if err != nil {
+				log.Println(err)
+				break
+			}
+			log.Printf("Version %s in Projekt %s released", ver, prj.Key)
+			if *flagComment != "" {
+				err := commentVersionIssues(prj, ver, c)
+				if err != nil {
+					log.Println(err)
+				}
+			}
+		case *flagCreateComp != "":
+			comp := *flagCreateComp
+			err := internal.CreateComponent(prj, comp, *flagCompLead, *flagCompAssignee, c)
 			if err != nil {
 				log.Println(err)
 			} else {
-				log.Printf("Version %s in Projekt %s released", ver, prj.Key)
+				log.Printf("Komponente %s in Projekt %s angelegt", comp, prj.Key)
+			}
+		case *flagUpdateComp != "":
+			comp := *flagUpdateComp
+			err := internal.UpdateComponent(prj, comp, *flagCompLead, *flagCompAssignee, c)
+			if err != nil {
+				log.Println(err)
+			} else {
+				log.Printf("Komponente %s in Projekt %s aktualisiert", comp, prj.Key)
+			}
+		case *flagDeleteComp != "":
+			comp := *flagDeleteComp
+			err := internal.DeleteComponent(prj, comp, *flagCompMoveTo, c)
+			if err != nil {
+				log.Println(err)
+			} else {
+				log.Printf("Komponente %s in Projekt %s gelöscht", comp, prj.Key)
 			}
 		default:
 			log.Printf("In Projekt %s nichts geändert", prj.Key)
@@ -107,6 +464,518 @@ func main() {
 	}
 }
 
+func releaseDate(relDate string) (string, error) {
+	if relDate == "" {
+		return time.Now().Format(layoutISO), nil
+	}
+	_, err := time.Parse(layoutISO, relDate)
+	if err != nil {
+		return "", fmt.Errorf("Das Release Datum '%s' hat nicht das richtige Format (JJJJ-MM-TT)", relDate)
+	}
+	return relDate, nil
+}
+
+func plannedChanges(prjs []*internal.Project, relDate string) []internal.Change {
+	var changes []internal.Change
+	for _, prj := range prjs {
+		switch {
+		case *flagInspectVersion != "":
+		case *flagCreateVersion != "":
+			changes = append(changes, internal.Change{Project: prj.Key, Object: "Version " + *flagCreateVersion, Operation: internal.OpCreate})
+		case *flagReleaseVersion != "":
+			changes = append(changes, internal.Change{Project: prj.Key, Object: "Version " + *flagReleaseVersion, Operation: internal.OpRelease, State: "Release Datum " + relDate})
+		case *flagCreateComp != "":
+			changes = append(changes, internal.Change{Project: prj.Key, Object: "Komponente " + *flagCreateComp, Operation: internal.OpCreate})
+		case *flagUpdateComp != "":
+			changes = append(changes, internal.Change{Project: prj.Key, Object: "Komponente " + *flagUpdateComp, Operation: internal.OpUpdate})
+		case *flagDeleteComp != "":
+			ch := internal.Change{Project: prj.Key, Object: "Komponente " + *flagDeleteComp, Operation: internal.OpDelete}
+			if *flagCompMoveTo != "" {
+				ch.State = "Vorgänge nach " + *flagCompMoveTo
+			}
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+func confirmChanges(changes []internal.Change) bool {
+	if *flagYes || !internal.Destructive(changes) {
+		return true
+	}
+	log.Println("Geplante Änderungen:")
+	for _, ch := range changes {
+		log.Printf("  %s", ch)
+	}
+	if !isTerminal(os.Stdin) {
+		log.Println("Destruktive Änderungen bitte mit -yes bestätigen")
+		return false
+	}
+	return confirm(fmt.Sprintf("%d Änderungen ausführen?", len(changes)))
+}
+
+func listJournal(journal *internal.Journal) error {
+	entries, err := journal.Entries("")
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, e := range entries {
+		ver := e.Version()
+		rows = append(rows, []string{e.RunId, e.Time.Format(time.RFC3339), e.Project(), ver.Name, string(e.Operation)})
+	}
+	return internal.WriteTable(os.Stdout, *flagOutput, []string{"run-id", "zeit", "projekt", "version", "änderung"}, rows)
+}
+
+func undo(runId string, journal *internal.Journal, c internal.RestClient) error {
+	entries, err := journal.Entries(runId)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("Keine Änderungen mit Run-Id %s im Journal", runId)
+	}
+	var changes []internal.Change
+	for _, e := range entries {
+		changes = append(changes, e.UndoChange())
+	}
+	if !confirmChanges(changes) {
+		return fmt.Errorf("Abgebrochen, keine Änderungen rückgängig gemacht")
+	}
+	errs := internal.Undo(entries, c)
+	for _, err := range errs {
+		log.Println(err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d von %d Änderungen nicht rückgängig gemacht", len(errs), len(entries))
+	}
+	log.Printf("%d Änderungen von Run-Id %s rückgängig gemacht", len(entries), runId)
+	return nil
+}
+
+func releaseReady(prj *internal.Project, ver string, c internal.RestClient) bool {
+	check, err := internal.CheckRelease(prj, ver, c)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	if check.Ready() {
+		return true
+	}
+	log.Println(check)
+	return confirm(fmt.Sprintf("Version %s in Projekt %s trotzdem releasen?", ver, prj.Key))
+}
+
+func listGitIssues(prjKeys []string, revRange string, c internal.RestClient) error {
+	keys, err := internal.GitIssueKeys(*flagGitDir, revRange)
+	if err != nil {
+		return err
+	}
+	fields := splitList(*flagFields)
+	issues, missing, err := internal.ValidateIssues(internal.FilterIssueKeys(keys, prjKeys), fields, c)
+	if err != nil {
+		return err
+	}
+	for _, k := range missing {
+		log.Printf("Vorgang %s in Jira nicht vorhanden", k)
+	}
+	return internal.WriteIssues(os.Stdout, *flagOutput, issues, fields)
+}
+
+func syncVersions(srcKey string, prjKeys []string, c internal.RestClient) error {
+	src, err := c.GetProject(srcKey)
+	if err != nil {
+		return fmt.Errorf("Vorlageprojekt %s kann nicht gelesen werden (%s)", srcKey, err)
+	}
+	var changes []internal.VersionChange
+	for _, pk := range prjKeys {
+		if pk == src.Key {
+			continue
+		}
+		prj, err := c.GetProject(pk)
+		if err != nil {
+			log.Printf("Projekt %s kann nicht gelesen werden (%s)", pk, err)
+			continue
+		}
+		prjChanges, err := internal.PlanVersionSync(src, prj, *flagSyncArchive)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if len(prjChanges) == 0 {
+			log.Printf("Projekt %s entspricht dem Vorlageprojekt %s", prj.Key, src.Key)
+		}
+		changes = append(changes, prjChanges...)
+	}
+	var summary []internal.Change
+	for _, ch := range changes {
+		summary = append(summary, ch.Change())
+	}
+	if !confirmChanges(summary) {
+		return fmt.Errorf("Abgebrochen, keine Projekte geändert")
+	}
+	for _, ch := range changes {
+		err := internal.ApplyVersionChange(ch, c)
+		if err != nil {
+			log.Println(err)
+		} else {
+			log.Printf("Ausgeführt: %s", ch)
+		}
+	}
+	return nil
+}
+
+func compareVersions(prjKeys []string, c internal.RestClient) error {
+	var prjs []*internal.Project
+	for _, pk := range prjKeys {
+		prj, err := c.GetProject(pk)
+		if err != nil {
+			return fmt.Errorf("Projekt %s kann nicht gelesen werden (%s)", pk, err)
+		}
+		prjs = append(prjs, prj)
+	}
+	vc := internal.CompareVersions(prjs)
+	header, rows := vc.Table()
+	err := internal.WriteTable(os.Stdout, *flagOutput, header, rows)
+	if err != nil {
+		return err
+	}
+	if mismatches := vc.Mismatches(); len(mismatches) > 0 {
+		return fmt.Errorf("Projektversionen %s weichen voneinander ab", strings.Join(mismatches, ", "))
+	}
+	return nil
+}
+
+func reportVersions(prjKeys []string, versions string, c internal.RestClient) error {
+	today := time.Now()
+	var reports []internal.VersionProgress
+	for _, pk := range prjKeys {
+		prj, err := c.GetProject(pk)
+		if err != nil {
+			return fmt.Errorf("Projekt %s kann nicht gelesen werden (%s)", pk, err)
+		}
+		for _, ver := range reportVersionNames(prj, versions) {
+			report, err := internal.VersionReport(prj, ver, *flagPointsField, today, c)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			reports = append(reports, *report)
+		}
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("Keine Projektversion %s in %s vorhanden", versions, strings.Join(prjKeys, ", "))
+	}
+	unit := internal.PointsUnit(*flagPointsField)
+	if *flagOutput == internal.FormatHTML {
+		return internal.WriteProgressHTML(os.Stdout, reports, unit, today)
+	}
+	header, rows := internal.ProgressTable(reports, unit)
+	return internal.WriteTable(os.Stdout, *flagOutput, header, rows)
+}
+
+// reportVersionNames expands "open" to the unreleased, not archived versions of the project.
+func reportVersionNames(prj *internal.Project, versions string) []string {
+	var names []string
+	for _, v := range splitList(versions) {
+		if v != "open" {
+			names = append(names, v)
+			continue
+		}
+		for _, ver := range prj.Versions {
+			if !ver.Released && !ver.Archived {
+				names = append(names, ver.Name)
+			}
+		}
+	}
+	return names
+}
+
+func listComponents(prjKeys []string, c internal.RestClient) error {
+	var rows [][]string
+	for _, pk := range prjKeys {
+		comps, err := c.GetComponents(pk)
+		if err != nil {
+			log.Printf("Komponenten von Projekt %s können nicht gelesen werden (%s)", pk, err)
+			continue
+		}
+		for _, comp := range comps {
+			rows = append(rows, []string{pk, comp.Name, comp.LeadAccountId, comp.AssigneeType, comp.Description})
+		}
+	}
+	return internal.WriteTable(os.Stdout, *flagOutput, []string{"projekt", "name", "verantwortlich", "bearbeiter", "beschreibung"}, rows)
+}
+
+func searchIssues(prjKeys []string, jql string, c internal.RestClient) error {
+	fields := splitList(*flagFields)
+	issues, err := internal.SearchIssues(internal.ProjectJQL(prjKeys, jql), fields, c)
+	if err != nil {
+		return err
+	}
+	return internal.WriteIssues(os.Stdout, *flagOutput, issues, fields)
+}
+
+func assignFixVersion(prjKeys []string, ver string, c internal.RestClient) error {
+	keys, err := collectIssueKeys(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range internal.AssignFixVersion(keys, ver, *flagSetFixVer, c) {
+		if r.Err != nil {
+			failed++
+			log.Printf("Vorgang %s: %s", r.Key, r.Err)
+		} else {
+			log.Printf("Version %s dem Vorgang %s zugeordnet", ver, r.Key)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Vorgängen nicht geändert", failed, len(keys))
+	}
+	return nil
+}
+
+func transitionIssues(keys []string, status string, c internal.RestClient) error {
+	values, err := internal.ParseFieldValues(*flagTransFields)
+	if err != nil {
+		return err
+	}
+	plans := internal.PlanTransitions(keys, status, values, c)
+	var changes []internal.Change
+	for _, p := range plans {
+		if p.Err == nil && !p.Skipped {
+			changes = append(changes, p.Change())
+		}
+	}
+	if !confirmChanges(changes) {
+		return fmt.Errorf("Abgebrochen, keine Vorgänge überführt")
+	}
+	failed := 0
+	for _, r := range internal.ApplyTransitions(plans, c) {
+		switch {
+		case r.Err != nil:
+			failed++
+			log.Printf("Vorgang %s: %s", r.Key, r.Err)
+		case r.Skipped:
+			log.Printf("Vorgang %s ist bereits in Status %s", r.Key, status)
+		default:
+			log.Printf("Vorgang %s in Status %s überführt", r.Key, status)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Vorgängen nicht überführt", failed, len(keys))
+	}
+	return nil
+}
+
+func editLabels(prjKeys []string, c internal.RestClient) error {
+	edit, err := internal.ParseLabelEdit(*flagAddLabels, *flagRemoveLabels, *flagRenameLabels)
+	if err != nil {
+		return err
+	}
+	keys, err := collectIssueKeys(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	plans := internal.PlanLabels(keys, edit, c)
+	var changes []internal.Change
+	for _, p := range plans {
+		if p.Err == nil {
+			changes = append(changes, p.Change())
+		}
+	}
+	if !confirmChanges(changes) {
+		return fmt.Errorf("Abgebrochen, keine Labels geändert")
+	}
+	results := internal.ApplyLabels(plans, c)
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			log.Printf("Vorgang %s: %s", r.Key, r.Err)
+		}
+	}
+	header, rows := internal.LabelTable(results)
+	log.Printf("%d von %d Vorgängen geändert", len(rows), len(keys))
+	if len(rows) > 0 {
+		err = internal.WriteTable(os.Stdout, *flagOutput, header, rows)
+		if err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Vorgängen nicht geändert", failed, len(keys))
+	}
+	return nil
+}
+
+func linkIssues(prjKeys []string, issueKey string, c internal.RestClient) error {
+	keys, err := collectIssueKeys(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	plans, err := internal.PlanLinks(issueKey, keys, *flagLinkType, c)
+	if err != nil {
+		return err
+	}
+	var changes []internal.Change
+	for _, p := range plans {
+		if !p.Skipped {
+			changes = append(changes, p.Change())
+		}
+	}
+	if !confirmChanges(changes) {
+		return fmt.Errorf("Abgebrochen, keine Vorgänge verknüpft")
+	}
+	results := internal.ApplyLinks(plans, c)
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			log.Printf("Vorgang %s: %s", r.Key, r.Err)
+		case r.Skipped:
+			log.Printf("Vorgang %s ist bereits verknüpft", r.Key)
+		default:
+			log.Printf("Vorgang %s %s %s", issueKey, *flagLinkType, r.Key)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Vorgängen nicht verknüpft", failed, len(keys))
+	}
+	return nil
+}
+
+func linkGraph(prjKeys []string, format string, c internal.RestClient) error {
+	keys, err := collectIssueKeys(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	issues, missing, err := internal.ValidateIssues(keys, []string{"summary", "issuelinks"}, c)
+	if err != nil {
+		return err
+	}
+	for _, k := range missing {
+		log.Printf("Vorgang %s ist nicht vorhanden", k)
+	}
+	edges, summaries := internal.LinkGraph(issues)
+	graph, err := internal.WriteGraph(format, edges, summaries)
+	if err != nil {
+		return err
+	}
+	fmt.Print(graph)
+	return nil
+}
+
+func commentIssues(keys []string, ver string, c internal.RestClient) error {
+	relDate, err := releaseDate(*flagReleaseDate)
+	if err != nil {
+		return err
+	}
+	vis, err := internal.ParseVisibility(*flagCommentVis)
+	if err != nil {
+		return err
+	}
+	if !confirmChanges(internal.CommentChanges(keys, vis)) {
+		return fmt.Errorf("Abgebrochen, keine Vorgänge kommentiert")
+	}
+	values := internal.TemplateValues{Version: ver, ReleaseDate: relDate}
+	failed := 0
+	for _, r := range internal.CommentIssues(keys, *flagComment, values, vis, c) {
+		if r.Err != nil {
+			failed++
+			log.Printf("Vorgang %s: %s", r.Key, r.Err)
+		} else {
+			log.Printf("Vorgang %s kommentiert", r.Key)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Vorgängen nicht kommentiert", failed, len(keys))
+	}
+	return nil
+}
+
+func commentVersionIssues(prj *internal.Project, ver string, c internal.RestClient) error {
+	issues, err := internal.VersionIssues(prj, ver, "", []string{"summary"}, c)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, i := range issues {
+		keys = append(keys, i.Key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return commentIssues(keys, ver, c)
+}
+
+func transitionVersionIssues(prj *internal.Project, ver, status string, c internal.RestClient) error {
+	issues, err := internal.VersionIssues(prj, ver, "statusCategory != Done", []string{"summary"}, c)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	var keys []string
+	for _, i := range issues {
+		keys = append(keys, i.Key)
+	}
+	return transitionIssues(keys, status, c)
+}
+
+func collectIssueKeys(prjKeys []string, c internal.RestClient) ([]string, error) {
+	var text []string
+	switch *flagIssueKeys {
+	case "":
+	case "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		text = append(text, string(b))
+	default:
+		text = append(text, *flagIssueKeys)
+	}
+	if *flagGitLog != "" {
+		keys, err := internal.GitIssueKeys(*flagGitDir, *flagGitLog)
+		if err != nil {
+			return nil, err
+		}
+		text = append(text, keys...)
+	}
+	if *flagSearch != "" {
+		issues, err := internal.SearchIssues(internal.ProjectJQL(prjKeys, *flagSearch), []string{"summary"}, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range issues {
+			text = append(text, i.Key)
+		}
+	}
+	if *flagVersionIssues != "" {
+		for _, pk := range prjKeys {
+			prj, err := c.GetProject(pk)
+			if err != nil {
+				return nil, fmt.Errorf("Projekt %s kann nicht gelesen werden (%s)", pk, err)
+			}
+			issues, err := internal.VersionIssues(prj, *flagVersionIssues, "", []string{"summary"}, c)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range issues {
+				text = append(text, i.Key)
+			}
+		}
+	}
+	keys := internal.FilterIssueKeys(internal.ExtractIssueKeys(strings.Join(text, "\n")), prjKeys)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("Keine Vorgänge der Projekte %s gefunden", strings.Join(prjKeys, ","))
+	}
+	return keys, nil
+}
+
 func createUserInfo(username, apiKey string) (*url.Userinfo, error) {
 	if username == "" || apiKey == "" {
 		return nil, fmt.Errorf("Bitte Jira-Usernamen und Passwort angeben:")
@@ -114,9 +983,139 @@ func createUserInfo(username, apiKey string) (*url.Userinfo, error) {
 	return url.UserPassword(username, apiKey), nil
 }
 
-func resolveProjects(projectKeys string) ([]string, error) {
-	if projectKeys == "" {
+func baseURL(cloudAlias, rawURL string) (*url.URL, error) {
+	if rawURL != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("Jira URL %s ist ungültig", rawURL)
+		}
+		return u, nil
+	}
+	if cloudAlias == "" {
+		return nil, fmt.Errorf("Bitte den Jira Cloud Alias angeben:")
+	}
+	return &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.atlassian.net", cloudAlias)}, nil
+}
+
+func openCache(cfg *internal.Config, ttl string) (*internal.Cache, error) {
+	if cfg.Cache == nil && ttl == "" {
+		return nil, nil
+	}
+	cc := internal.CacheConfig{}
+	if cfg.Cache != nil {
+		cc = *cfg.Cache
+	}
+	if ttl != "" {
+		cc.TTL = ttl
+	}
+	return internal.NewCache(cc)
+}
+
+// writesJira reports whether the run may change Jira. Such runs read without cache, the
+// checks and journal snapshots ahead of a write must see changes made outside of jiratool.
+func writesJira() bool {
+	for _, f := range []string{*flagUndo, *flagAssignFixVer, *flagTransition, *flagComment, *flagAddLabels, *flagRemoveLabels,
+		*flagRenameLabels, *flagLinkIssue, *flagSyncVersions, *flagNextSprint, *flagStartSprint, *flagCloseSprint,
+		*flagCreateVersion, *flagReleaseVersion, *flagCreateComp, *flagUpdateComp, *flagDeleteComp} {
+		if f != "" {
+			return true
+		}
+	}
+	return *flagTUI
+}
+
+func roundTripper(c *http.Client) http.RoundTripper {
+	if c.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.Transport
+}
+
+func openTrace(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stderr, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Protokoll %s kann nicht geöffnet werden (%s)", path, err)
+	}
+	return f, nil
+}
+
+func transportConfig(cfg *internal.Config) internal.TransportConfig {
+	tc := cfg.Transport
+	if *flagProxy != "" {
+		tc.Proxy = *flagProxy
+	}
+	if *flagCACert != "" {
+		tc.CACert = *flagCACert
+	}
+	if *flagClientCert != "" {
+		tc.ClientCert = *flagClientCert
+	}
+	if *flagClientKey != "" {
+		tc.ClientKey = *flagClientKey
+	}
+	return tc
+}
+
+func loadConfig(path string) (*internal.Config, error) {
+	if path != "" {
+		return internal.LoadConfig(path)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return &internal.Config{}, nil
+	}
+	cfg, err := internal.LoadConfig(filepath.Join(home, configFile))
+	if os.IsNotExist(err) {
+		return &internal.Config{}, nil
+	}
+	return cfg, err
+}
+
+func openJournal(cfg *internal.Config) (*internal.Journal, error) {
+	path, err := dataFile(cfg.Journal, journalFile)
+	if err != nil {
+		return nil, fmt.Errorf("Journal kann nicht angelegt werden (%s)", err)
+	}
+	return &internal.Journal{Path: path}, nil
+}
+
+func openAuditLog(cfg *internal.Config) (*internal.AuditLog, error) {
+	path, err := dataFile(cfg.Audit, auditFile)
+	if err != nil {
+		return nil, fmt.Errorf("Audit-Log kann nicht angelegt werden (%s)", err)
+	}
+	return &internal.AuditLog{Path: path}, nil
+}
+
+func dataFile(path, name string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, name), nil
+}
+
+func resolveProjects(projectKeys string, cfg *internal.Config) ([]string, error) {
+	prjKeys := cfg.ExpandProjects(strings.Split(projectKeys, ","))
+	if len(prjKeys) == 0 {
 		return nil, fmt.Errorf("Bitte mindestens ein Jira-Projekt angeben")
 	}
-	return strings.Split(projectKeys, ","), nil
+	return prjKeys, nil
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
 }
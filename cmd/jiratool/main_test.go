@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bitbucket.org/christian_m/jiratool/internal"
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCreateUserInfo(t *testing.T) {
@@ -81,10 +83,29 @@ func TestResolveProjects(t *testing.T) {
 			nil,
 			true,
 		},
+		{
+			"project group",
+			"backend",
+			[]string{"API", "DB", "AUTH"},
+			false,
+		},
+		{
+			"project group mixed with projects",
+			"REL,backend,DB",
+			[]string{"REL", "API", "DB", "AUTH"},
+			false,
+		},
+		{
+			"duplicate projects",
+			"DB, DB,MN",
+			[]string{"DB", "MN"},
+			false,
+		},
 	}
+	cfg := &internal.Config{Groups: map[string][]string{"backend": {"API", "DB", "AUTH"}}}
 	for _, tt := range tests {
 		t.Run(tt.testcase, func(t *testing.T) {
-			projects, err := resolveProjects(tt.projectString)
+			projects, err := resolveProjects(tt.projectString, cfg)
 			switch {
 			case err != nil && !tt.err:
 				t.Errorf("got: %v - want: no Error", err)
@@ -94,3 +115,66 @@ func TestResolveProjects(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseDate(t *testing.T) {
+	tests := []struct {
+		testcase    string
+		releaseDate string
+		result      string
+		err         bool
+	}{
+		{
+			"valid release date",
+			"2021-07-06",
+			"2021-07-06",
+			false,
+		},
+		{
+			"today",
+			"",
+			time.Now().Format(layoutISO),
+			false,
+		},
+		{
+			"invalid release date",
+			"06.07.2021",
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			result, err := releaseDate(tt.releaseDate)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no Error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no Error - want: Error")
+			case result != tt.result:
+				t.Errorf("Got: %v - Want: %v", result, tt.result)
+			}
+		})
+	}
+}
+
+func TestWritesJira(t *testing.T) {
+	tests := []struct {
+		flag  *string
+		value string
+		write bool
+	}{
+		{flagSearch, "fixVersion = 2021-07", false},
+		{flagInspectVersion, "2021-07", false},
+		{flagReleaseVersion, "2021-07", true},
+		{flagRenameLabels, "alt=neu", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			*tt.flag = tt.value
+			defer func() { *tt.flag = "" }()
+			if writesJira() != tt.write {
+				t.Errorf("got: %v - want: %v", !tt.write, tt.write)
+			}
+		})
+	}
+}
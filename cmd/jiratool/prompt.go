@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func confirm(question string) bool {
+	if !isTerminal(os.Stdin) {
+		return false
+	}
+	return askYesNo(stdin, os.Stderr, question)
+}
+
+func askYesNo(r *bufio.Reader, w io.Writer, question string) bool {
+	_, _ = fmt.Fprintf(w, "%s [j/N] ", question)
+	answer, err := r.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "j", "ja", "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
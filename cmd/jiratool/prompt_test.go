@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAskYesNo(t *testing.T) {
+	tests := []struct {
+		testcase string
+		input    string
+		answer   bool
+	}{
+		{"yes german", "j\n", true},
+		{"yes english", "Yes\n", true},
+		{"no", "n\n", false},
+		{"empty answer", "\n", false},
+		{"no input", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			answer := askYesNo(bufio.NewReader(strings.NewReader(tt.input)), io.Discard, "Weiter?")
+			if answer != tt.answer {
+				t.Errorf("got: %v - want: %v", answer, tt.answer)
+			}
+		})
+	}
+}
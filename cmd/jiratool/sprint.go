@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+type projectBoards struct {
+	prjKey  string
+	board   internal.Board
+	sprints []internal.Sprint
+}
+
+// scrumBoards reads the scrum boards of the projects once, boards shared by projects are listed for the first one.
+func scrumBoards(prjKeys []string, c internal.RestClient) ([]projectBoards, error) {
+	var boards []projectBoards
+	seen := make(map[int]bool)
+	for _, pk := range prjKeys {
+		bs, err := internal.ScrumBoards(pk, c)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) == 0 {
+			log.Printf("Projekt %s hat kein Scrum Board", pk)
+		}
+		for _, b := range bs {
+			if seen[b.Id] {
+				continue
+			}
+			seen[b.Id] = true
+			sprints, err := c.GetSprints(b.Id)
+			if err != nil {
+				return nil, fmt.Errorf("Sprints von Board %s können nicht gelesen werden (%s)", b.Name, err)
+			}
+			boards = append(boards, projectBoards{prjKey: pk, board: b, sprints: sprints})
+		}
+	}
+	return boards, nil
+}
+
+func listBoards(prjKeys []string, c internal.RestClient) error {
+	boards, err := scrumBoards(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	var bs []internal.Board
+	sprints := make(map[int][]internal.Sprint)
+	for _, pb := range boards {
+		bs = append(bs, pb.board)
+		sprints[pb.board.Id] = pb.sprints
+	}
+	header, rows := internal.SprintTable(bs, sprints)
+	return internal.WriteTable(os.Stdout, *flagOutput, header, rows)
+}
+
+func manageSprints(prjKeys []string, c internal.RestClient) error {
+	boards, err := scrumBoards(prjKeys, c)
+	if err != nil {
+		return err
+	}
+	length := time.Duration(*flagSprintLength) * 24 * time.Hour
+	now := time.Now()
+	var planned []internal.SprintChange
+	for _, pb := range boards {
+		var ch *internal.SprintChange
+		var err error
+		switch {
+		case *flagNextSprint != "":
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+			ch, err = internal.PlanNextSprint(pb.prjKey, pb.board, pb.sprints, *flagNextSprint, length, today)
+			if ch != nil {
+				ch.Version = *flagSprintVersion
+			}
+		case *flagStartSprint != "":
+			ch, err = internal.PlanStartSprint(pb.prjKey, pb.board, pb.sprints, *flagStartSprint, length, now)
+		case *flagCloseSprint != "":
+			ch, err = internal.PlanCloseSprint(pb.prjKey, pb.board, pb.sprints, *flagCloseSprint)
+		}
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		planned = append(planned, *ch)
+	}
+	if len(planned) == 0 {
+		log.Println("Keine Sprints geändert")
+		return nil
+	}
+	var changes []internal.Change
+	for _, ch := range planned {
+		changes = append(changes, ch.Change())
+	}
+	if !confirmChanges(changes) {
+		return fmt.Errorf("Abgebrochen, keine Sprints geändert")
+	}
+	failed := 0
+	for _, ch := range planned {
+		if err := internal.ApplySprintChange(ch, c); err != nil {
+			log.Printf("%s: %s", ch, err)
+			failed++
+			continue
+		}
+		log.Printf("Ausgeführt: %s", ch)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d von %d Sprint Änderungen fehlgeschlagen", failed, len(planned))
+	}
+	return nil
+}
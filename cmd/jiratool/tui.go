@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tuiProjectHelp = "Projekt wählen: <Nr> öffnen | q beenden"
+	tuiVersionHelp = "Version wählen: <Nr> anzeigen | n <Name> anlegen | r <Nr> releasen | a <Nr> archivieren | m <Nr> <first|earlier|later|last> verschieben | b zurück | q beenden"
+)
+
+type tui struct {
+	in      *bufio.Reader
+	out     io.Writer
+	c       internal.RestClient
+	prjKeys []string
+}
+
+func (t *tui) run() {
+	for {
+		_, _ = fmt.Fprintln(t.out)
+		for i, pk := range t.prjKeys {
+			_, _ = fmt.Fprintf(t.out, "%3d  %s\n", i+1, pk)
+		}
+		cmd, _, ok := t.prompt(tuiProjectHelp)
+		if !ok || cmd == "q" {
+			return
+		}
+		if cmd == "" {
+			continue
+		}
+		n, err := strconv.Atoi(cmd)
+		if err != nil || n < 1 || n > len(t.prjKeys) {
+			_, _ = fmt.Fprintf(t.out, "Eingabe '%s' ist ungültig\n", cmd)
+			continue
+		}
+		if t.browseProject(t.prjKeys[n-1]) {
+			return
+		}
+	}
+}
+
+func (t *tui) browseProject(pk string) bool {
+	for {
+		prj, err := t.c.GetProject(pk)
+		if err != nil {
+			_, _ = fmt.Fprintf(t.out, "Projekt %s kann nicht gelesen werden (%s)\n", pk, err)
+			return false
+		}
+		_, _ = fmt.Fprintf(t.out, "\nProjekt %s\n", prj.Key)
+		for i, v := range prj.Versions {
+			_, _ = fmt.Fprintf(t.out, "%3d  %s\n", i+1, internal.VersionSummary(v))
+		}
+		cmd, args, ok := t.prompt(tuiVersionHelp)
+		if !ok {
+			return true
+		}
+		switch cmd {
+		case "":
+		case "q":
+			return true
+		case "b":
+			return false
+		case "n":
+			if len(args) != 1 {
+				_, _ = fmt.Fprintln(t.out, "Bitte den Namen der Version angeben")
+				break
+			}
+			t.execute(fmt.Sprintf("Version %s in Projekt %s anlegen?", args[0], prj.Key), func() error {
+				return internal.CreateVersion(prj, args[0], t.c)
+			})
+		case "r", "a", "m":
+			ver, ok := t.selectVersion(prj, args)
+			if !ok {
+				break
+			}
+			switch {
+			case cmd == "r":
+				t.release(prj, ver)
+			case cmd == "a":
+				t.execute(fmt.Sprintf("Version %s in Projekt %s archivieren?", ver.Name, prj.Key), func() error {
+					return internal.ArchiveVersion(prj, ver.Name, t.c)
+				})
+			case len(args) != 2:
+				_, _ = fmt.Fprintln(t.out, "Bitte die Position angeben (first, earlier, later, last)")
+			default:
+				t.execute(fmt.Sprintf("Version %s in Projekt %s verschieben (%s)?", ver.Name, prj.Key, args[1]), func() error {
+					return internal.MoveVersion(prj, ver.Name, args[1], t.c)
+				})
+			}
+		default:
+			ver, ok := t.selectVersion(prj, []string{cmd})
+			if ok {
+				t.showVersion(prj, ver)
+			}
+		}
+	}
+}
+
+func (t *tui) showVersion(prj *internal.Project, ver internal.Version) {
+	check, err := internal.CheckRelease(prj, ver.Name, t.c)
+	if err != nil {
+		_, _ = fmt.Fprintln(t.out, err)
+		return
+	}
+	_, _ = fmt.Fprintf(t.out, "\n%s\n", internal.VersionSummary(ver))
+	_, _ = fmt.Fprintf(t.out, "Vorgänge: %d mit fixVersion, %d mit affectedVersion, %d offen\n", check.IssuesFixed, check.IssuesAffected, check.IssuesUnresolved)
+	if !check.Ready() {
+		_, _ = fmt.Fprintln(t.out, check)
+	}
+}
+
+func (t *tui) release(prj *internal.Project, ver internal.Version) {
+	check, err := internal.CheckRelease(prj, ver.Name, t.c)
+	if err != nil {
+		_, _ = fmt.Fprintln(t.out, err)
+		return
+	}
+	_, _ = fmt.Fprintln(t.out, check)
+	relDate := time.Now().Format(layoutISO)
+	t.execute(fmt.Sprintf("Version %s in Projekt %s am %s releasen?", ver.Name, prj.Key, relDate), func() error {
+		return internal.ReleaseVersion(prj, ver.Name, relDate, t.c)
+	})
+}
+
+func (t *tui) execute(question string, action func() error) {
+	if !askYesNo(t.in, t.out, question) {
+		_, _ = fmt.Fprintln(t.out, "Abgebrochen")
+		return
+	}
+	if err := action(); err != nil {
+		_, _ = fmt.Fprintln(t.out, err)
+		return
+	}
+	_, _ = fmt.Fprintln(t.out, "Erledigt")
+}
+
+func (t *tui) selectVersion(prj *internal.Project, args []string) (internal.Version, bool) {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(t.out, "Bitte die Nummer der Version angeben")
+		return internal.Version{}, false
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(prj.Versions) {
+		_, _ = fmt.Fprintf(t.out, "Version '%s' ist ungültig\n", args[0])
+		return internal.Version{}, false
+	}
+	return prj.Versions[n-1], true
+}
+
+func (t *tui) prompt(help string) (string, []string, bool) {
+	_, _ = fmt.Fprintf(t.out, "%s\n> ", help)
+	line, err := t.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", nil, false
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil, true
+	}
+	return strings.ToLower(fields[0]), fields[1:], true
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTui(t *testing.T) {
+	tests := []struct {
+		testcase string
+		input    string
+		versions []internal.Version
+		output   []string
+	}{
+		{
+			"create version",
+			"1\nn 2021-08\nj\nb\nq\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07"}, {Name: "2021-08", ProjectId: 10000}},
+			[]string{"Projekt DB", "  1  2021-07 (offen)", "Erledigt", "  2  2021-08 (offen)"},
+		},
+		{
+			"release version",
+			"1\nr 1\nj\nq\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07", Released: true}},
+			[]string{"hat keine offenen Vorgänge", "releasen?", "Erledigt", "2021-07 (released"},
+		},
+		{
+			"archive version cancelled",
+			"1\na 1\nn\nq\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07"}},
+			[]string{"archivieren?", "Abgebrochen"},
+		},
+		{
+			"show version",
+			"1\n1\nq\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07"}},
+			[]string{"Vorgänge: 3 mit fixVersion, 1 mit affectedVersion, 0 offen"},
+		},
+		{
+			"invalid input",
+			"7\n1\nr 9\nm 1\nq\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07"}},
+			[]string{"Eingabe '7' ist ungültig", "Version '9' ist ungültig", "Bitte die Position angeben"},
+		},
+		{
+			"end of input",
+			"1\n",
+			[]internal.Version{{Id: "10001", Name: "2021-07"}},
+			[]string{"Projekt DB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &tuiRestClient{prj: internal.Project{Id: "10000", Key: "DB", Versions: []internal.Version{{Id: "10001", Name: "2021-07"}}}}
+			out := new(bytes.Buffer)
+			ui := &tui{in: bufio.NewReader(strings.NewReader(tt.input)), out: out, c: c, prjKeys: []string{"DB"}}
+			ui.run()
+			for _, o := range tt.output {
+				if !strings.Contains(out.String(), o) {
+					t.Errorf("got: %s - want: %s", out.String(), o)
+				}
+			}
+			if len(c.prj.Versions) != len(tt.versions) {
+				t.Fatalf("got: %v - want: %v", c.prj.Versions, tt.versions)
+			}
+			for i, v := range tt.versions {
+				if c.prj.Versions[i].Name != v.Name || c.prj.Versions[i].Released != v.Released {
+					t.Errorf("got: %v - want: %v", c.prj.Versions[i], v)
+				}
+			}
+		})
+	}
+}
+
+type tuiRestClient struct {
+	internal.RestClient
+	prj internal.Project
+}
+
+func (c *tuiRestClient) GetProject(prjKey string) (*internal.Project, error) {
+	prj := c.prj
+	prj.Versions = append([]internal.Version{}, c.prj.Versions...)
+	return &prj, nil
+}
+
+func (c *tuiRestClient) CreateVersion(version internal.Version) error {
+	c.prj.Versions = append(c.prj.Versions, version)
+	return nil
+}
+
+func (c *tuiRestClient) UpdateVersion(version internal.Version) error {
+	for i, v := range c.prj.Versions {
+		if v.Id == version.Id {
+			c.prj.Versions[i] = version
+		}
+	}
+	return nil
+}
+
+func (c *tuiRestClient) GetVersionRelatedIssueCounts(verId string) (*internal.VersionRelatedIssueCounts, error) {
+	return &internal.VersionRelatedIssueCounts{IssuesFixedCount: 3, IssuesAffectedCount: 1}, nil
+}
+
+func (c *tuiRestClient) GetVersionUnresolvedIssueCount(verId string) (*internal.VersionUnresolvedIssueCount, error) {
+	return &internal.VersionUnresolvedIssueCount{IssuesCount: 3}, nil
+}
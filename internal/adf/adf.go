@@ -0,0 +1,158 @@
+// Package adf converts the Atlassian Document Format used by the Jira REST API v3 for
+// descriptions and comments to Markdown and plain text, and Markdown to ADF.
+package adf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeDoc         = "doc"
+	TypeParagraph   = "paragraph"
+	TypeText        = "text"
+	TypeHeading     = "heading"
+	TypeBulletList  = "bulletList"
+	TypeOrderedList = "orderedList"
+	TypeListItem    = "listItem"
+	TypeCodeBlock   = "codeBlock"
+	TypeBlockquote  = "blockquote"
+	TypeRule        = "rule"
+	TypeHardBreak   = "hardBreak"
+	TypeMention     = "mention"
+	TypeEmoji       = "emoji"
+	TypeInlineCard  = "inlineCard"
+	TypeTable       = "table"
+	TypeTableRow    = "tableRow"
+	TypeTableHeader = "tableHeader"
+	TypeTableCell   = "tableCell"
+	TypePanel       = "panel"
+	TypeStatus      = "status"
+	TypeDate        = "date"
+
+	MarkStrong = "strong"
+	MarkEm     = "em"
+	MarkCode   = "code"
+	MarkStrike = "strike"
+	MarkLink   = "link"
+)
+
+type Node struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+}
+
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// MarshalJSON writes the content of a document even if it is empty, Jira rejects
+// documents without content.
+func (n Node) MarshalJSON() ([]byte, error) {
+	type node Node
+	if n.Type != TypeDoc {
+		return json.Marshal(node(n))
+	}
+	content := n.Content
+	if content == nil {
+		content = []Node{}
+	}
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Version int    `json:"version"`
+		Content []Node `json:"content"`
+	}{TypeDoc, 1, content})
+}
+
+// Doc returns a document of the blocks.
+func Doc(blocks ...Node) *Node {
+	return &Node{Type: TypeDoc, Version: 1, Content: blocks}
+}
+
+// Parse reads a document from a decoded JSON value, e.g. an issue field.
+func Parse(v interface{}) (*Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Node{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("Dokument ist ungültig (%s)", err)
+	}
+	if doc.Type != TypeDoc {
+		return nil, fmt.Errorf("Dokument ist ungültig (Typ %s statt doc)", doc.Type)
+	}
+	return doc, nil
+}
+
+// IsDoc reports whether a decoded JSON value is a document.
+func IsDoc(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	return ok && m["type"] == TypeDoc
+}
+
+func (n Node) attr(name string) string {
+	switch v := n.Attrs[name].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (n Node) intAttr(name string, def int) int {
+	switch v := n.Attrs[name].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return def
+	}
+}
+
+func (n Node) mark(markType string) *Mark {
+	for i := range n.Marks {
+		if n.Marks[i].Type == markType {
+			return &n.Marks[i]
+		}
+	}
+	return nil
+}
+
+func (m Mark) attr(name string) string {
+	s, _ := m.Attrs[name].(string)
+	return s
+}
+
+// ReplaceText returns a copy of the node with the replacer applied to the texts and link
+// targets, e.g. to fill placeholders after parsing so that values are not read as markup.
+func ReplaceText(n Node, r *strings.Replacer) Node {
+	n.Text = r.Replace(n.Text)
+	if n.Marks != nil {
+		marks := make([]Mark, len(n.Marks))
+		for i, m := range n.Marks {
+			if href, ok := m.Attrs["href"].(string); ok {
+				m.Attrs = map[string]interface{}{"href": r.Replace(href)}
+			}
+			marks[i] = m
+		}
+		n.Marks = marks
+	}
+	if n.Content != nil {
+		content := make([]Node, len(n.Content))
+		for i, c := range n.Content {
+			content[i] = ReplaceText(c, r)
+		}
+		n.Content = content
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+package adf
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const descriptionMarkdown = "## Release 1.2.0\n\n" +
+	"Deployment durch @[Fake Jira](5b10ac8d82e05b22cc7d4ef5) am **Montag**, siehe [Runbook](https://example.com/runbook) und <https://example.com>.\\\n" +
+	"Feld `fix_version` ist ~~alt~~ *neu*\n\n" +
+	"- Backend\n" +
+	"  3. Datenbank\n" +
+	"  4. Cache\n" +
+	"- Frontend\n\n" +
+	"```sh\njiratool -p DEMO -rv 1.2.0\necho ok\n```\n\n" +
+	"> Nicht freitags deployen\n\n" +
+	"---\n\n" +
+	"| Projekt | Status |\n" +
+	"| --- | --- |\n" +
+	"| DEMO | DONE a\\|b |\n\n" +
+	"😄 1. Platz am 2021-06-01"
+
+const descriptionText = "Release 1.2.0\n\n" +
+	"Deployment durch @Fake Jira am Montag, siehe Runbook (https://example.com/runbook) und https://example.com.\n" +
+	"Feld fix_version ist alt neu\n\n" +
+	"- Backend\n" +
+	"  3. Datenbank\n" +
+	"  4. Cache\n" +
+	"- Frontend\n\n" +
+	"jiratool -p DEMO -rv 1.2.0\necho ok\n\n" +
+	"> Nicht freitags deployen\n\n" +
+	"---\n\n" +
+	"Projekt | Status\n" +
+	"DEMO | DONE a|b\n\n" +
+	"😄 1. Platz am 2021-06-01"
+
+func loadDescription(t *testing.T) *Node {
+	t.Helper()
+	data, err := os.ReadFile("testdata/description.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if !IsDoc(v) {
+		t.Fatalf("got: %v - want: document", v)
+	}
+	doc, err := Parse(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestToMarkdown(t *testing.T) {
+	if got := ToMarkdown(loadDescription(t)); got != descriptionMarkdown {
+		t.Errorf("got:\n%s\n- want:\n%s", got, descriptionMarkdown)
+	}
+}
+
+func TestToText(t *testing.T) {
+	if got := ToText(loadDescription(t)); got != descriptionText {
+		t.Errorf("got:\n%s\n- want:\n%s", got, descriptionText)
+	}
+}
+
+func TestFromMarkdown_RoundTrip(t *testing.T) {
+	tests := []struct {
+		testcase string
+		markdown string
+	}{
+		{"description", descriptionMarkdown},
+		{"escaped text", `2\*3 \[nicht\] \_kursiv\_ a\\b`},
+		{"escaped line start", "\\# kein Titel\n\n1\\. kein Punkt"},
+		{"nested emphasis", "***fett und kursiv*** und **fett mit *kursiv***"},
+		{"loose list", "1. eins\n\n   Absatz\n2. zwei"},
+		{"code with backticks", "``a`b`` und\n\n````\n```\n````"},
+		{"link with marks", "[**fett** und `code`](https://example.com)"},
+		{"table without header", "|  |  |\n| --- | --- |\n| a | b<br>c |"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			if got := ToMarkdown(FromMarkdown(tt.markdown)); got != tt.markdown {
+				t.Errorf("got:\n%s\n- want:\n%s", got, tt.markdown)
+			}
+		})
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	tests := []struct {
+		testcase string
+		markdown string
+		want     []Node
+	}{
+		{
+			"paragraph with soft break",
+			"Zeile eins\nZeile zwei  \nZeile drei",
+			[]Node{{Type: TypeParagraph, Content: []Node{
+				{Type: TypeText, Text: "Zeile eins Zeile zwei"}, {Type: TypeHardBreak}, {Type: TypeText, Text: "Zeile drei"},
+			}}},
+		},
+		{
+			"intraword underscore and unclosed delimiters",
+			"snake_case_name und 2 * 3 und **offen",
+			[]Node{{Type: TypeParagraph, Content: []Node{{Type: TypeText, Text: "snake_case_name und 2 * 3 und **offen"}}}},
+		},
+		{
+			"heading and lazy list continuation",
+			"# Titel #\n- eins\nweiter\n- zwei",
+			[]Node{
+				{Type: TypeHeading, Attrs: map[string]interface{}{"level": 1}, Content: []Node{{Type: TypeText, Text: "Titel"}}},
+				{Type: TypeBulletList, Content: []Node{
+					{Type: TypeListItem, Content: []Node{{Type: TypeParagraph, Content: []Node{{Type: TypeText, Text: "eins weiter"}}}}},
+					{Type: TypeListItem, Content: []Node{{Type: TypeParagraph, Content: []Node{{Type: TypeText, Text: "zwei"}}}}},
+				}},
+			},
+		},
+		{
+			"link with parentheses",
+			"[x](http://x/a_(b)) (c)",
+			[]Node{{Type: TypeParagraph, Content: []Node{
+				{Type: TypeText, Text: "x", Marks: []Mark{{Type: MarkLink, Attrs: map[string]interface{}{"href": "http://x/a_(b)"}}}},
+				{Type: TypeText, Text: " (c)"},
+			}}},
+		},
+		{
+			"mention",
+			"@[Fake Jira](5b10ac8d)",
+			[]Node{{Type: TypeParagraph, Content: []Node{{Type: TypeMention, Attrs: map[string]interface{}{"id": "5b10ac8d", "text": "@Fake Jira"}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			if got := FromMarkdown(tt.markdown); !reflect.DeepEqual(got.Content, tt.want) {
+				t.Errorf("got: %+v - want: %+v", got.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMarkdown_UnclosedDelimiters(t *testing.T) {
+	for _, markdown := range []string{strings.Repeat("*_~~a", 200), strings.Repeat("**a _b ~~c ", 200)} {
+		start := time.Now()
+		FromMarkdown(markdown)
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("got: %s - want: each unclosed delimiter parsed once", d)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(FromMarkdown(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"type":"doc","version":1,"content":[]}`; string(data) != want {
+		t.Errorf("got: %s - want: %s", data, want)
+	}
+	data, _ = json.Marshal(FromMarkdown("**a**"))
+	if want := `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"a","marks":[{"type":"strong"}]}]}]}`; string(data) != want {
+		t.Errorf("got: %s - want: %s", data, want)
+	}
+}
@@ -0,0 +1,265 @@
+package adf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "~", `\~`, "<", `\<`)
+
+// ToMarkdown renders a document as Markdown. Mentions are written as @[name](account id).
+func ToMarkdown(doc *Node) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimRight(markdownBlocks(doc.Content), "\n")
+}
+
+func markdownBlocks(blocks []Node) string {
+	var parts []string
+	for _, b := range blocks {
+		if s := markdownBlock(b); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func markdownBlock(n Node) string {
+	switch n.Type {
+	case TypeParagraph:
+		return escapeLineStart(markdownInline(n.Content))
+	case TypeHeading:
+		return strings.Repeat("#", n.intAttr("level", 1)) + " " + markdownInline(n.Content)
+	case TypeBulletList, TypeOrderedList:
+		return markdownList(n)
+	case TypeCodeBlock:
+		text := plainInline(n.Content)
+		fence := "```"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		return fence + n.attr("language") + "\n" + text + "\n" + fence
+	case TypeBlockquote, TypePanel:
+		return prefixLines(markdownBlocks(n.Content), "> ", ">")
+	case TypeRule:
+		return "---"
+	case TypeTable:
+		return markdownTable(n)
+	default:
+		if len(n.Content) > 0 && isBlock(n.Content[0]) {
+			return markdownBlocks(n.Content)
+		}
+		return markdownInline([]Node{n})
+	}
+}
+
+func markdownList(n Node) string {
+	start := n.intAttr("order", 1)
+	var items []string
+	for i, item := range n.Content {
+		marker := "- "
+		if n.Type == TypeOrderedList {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		indent := strings.Repeat(" ", len(marker))
+		body := prefixLines(itemBlocks(item.Content, markdownBlock), indent, "")
+		items = append(items, marker+strings.TrimPrefix(body, indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+// itemBlocks joins the blocks of a list item, nested lists follow without a blank line.
+func itemBlocks(blocks []Node, render func(Node) string) string {
+	var sb strings.Builder
+	for i, b := range blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+			if b.Type != TypeBulletList && b.Type != TypeOrderedList {
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString(render(b))
+	}
+	return sb.String()
+}
+
+func markdownTable(n Node) string {
+	var rows [][]string
+	header := false
+	for i, row := range n.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			if i == 0 && cell.Type == TypeTableHeader {
+				header = true
+			}
+			cells = append(cells, markdownCell(cell))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	if !header {
+		rows = append([][]string{make([]string, len(rows[0]))}, rows...)
+	}
+	var lines []string
+	for i, cells := range rows {
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			sep := make([]string, len(cells))
+			for j := range sep {
+				sep[j] = "---"
+			}
+			lines = append(lines, "| "+strings.Join(sep, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownCell writes the blocks of a cell in one line, Markdown tables have no line breaks.
+func markdownCell(cell Node) string {
+	var parts []string
+	for _, b := range cell.Content {
+		s := strings.ReplaceAll(markdownBlock(b), "|", `\|`)
+		parts = append(parts, strings.NewReplacer("\\\n", "<br>", "\n", "<br>").Replace(s))
+	}
+	return strings.Join(parts, "<br>")
+}
+
+// markdownDelimiters are the marks written around runs of nodes, outermost first.
+var markdownDelimiters = []struct {
+	mark  string
+	delim string
+}{{MarkLink, ""}, {MarkStrike, "~~"}, {MarkStrong, "**"}, {MarkEm, "*"}}
+
+func markdownInline(nodes []Node) string {
+	return markdownMarked(nodes, 0)
+}
+
+// markdownMarked writes the runs of nodes sharing a mark inside one pair of delimiters,
+// so that adjacent text with different marks nests, e.g. **fett *kursiv***.
+func markdownMarked(nodes []Node, level int) string {
+	if level == len(markdownDelimiters) {
+		var sb strings.Builder
+		for _, n := range nodes {
+			sb.WriteString(markdownInlineNode(n))
+		}
+		return sb.String()
+	}
+	d := markdownDelimiters[level]
+	key := func(n Node) (string, bool) {
+		m := n.mark(d.mark)
+		if m == nil || n.mark(MarkCode) != nil && d.mark != MarkLink {
+			return "", false
+		}
+		return m.attr("href"), true
+	}
+	var sb strings.Builder
+	for i := 0; i < len(nodes); {
+		k, marked := key(nodes[i])
+		j := i + 1
+		for ; j < len(nodes); j++ {
+			if nk, nm := key(nodes[j]); nk != k || nm != marked {
+				break
+			}
+		}
+		run := nodes[i:j]
+		inner := markdownMarked(run, level+1)
+		switch {
+		case !marked:
+			sb.WriteString(inner)
+		case d.mark != MarkLink:
+			sb.WriteString(d.delim + inner + d.delim)
+		case len(run) == 1 && run[0].Type == TypeText && run[0].Text == k && len(run[0].Marks) == 1:
+			sb.WriteString("<" + k + ">")
+		default:
+			sb.WriteString("[" + inner + "](" + k + ")")
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+func markdownInlineNode(n Node) string {
+	switch n.Type {
+	case TypeText:
+		if n.mark(MarkCode) != nil {
+			fence := "`"
+			for strings.Contains(n.Text, fence) {
+				fence += "`"
+			}
+			if strings.HasPrefix(n.Text, "`") || strings.HasSuffix(n.Text, "`") {
+				return fence + " " + n.Text + " " + fence
+			}
+			return fence + n.Text + fence
+		}
+		return markdownEscaper.Replace(n.Text)
+	case TypeHardBreak:
+		return "\\\n"
+	case TypeMention:
+		return "@[" + markdownEscaper.Replace(mentionName(n)) + "](" + n.attr("id") + ")"
+	case TypeInlineCard:
+		return "<" + n.attr("url") + ">"
+	default:
+		return markdownEscaper.Replace(plainInline([]Node{n}))
+	}
+}
+
+// escapeLineStart escapes a paragraph that would otherwise start a heading, list, quote or rule.
+func escapeLineStart(s string) string {
+	t := strings.TrimLeft(s, " ")
+	switch {
+	case t == "":
+		return s
+	case strings.ContainsRune("#>-+", rune(t[0])):
+		return `\` + t
+	}
+	if m := listItemRegexp.FindString(t); m != "" {
+		digits := strings.TrimRight(m, " ")
+		return digits[:len(digits)-1] + `\` + t[len(digits)-1:]
+	}
+	return s
+}
+
+func mentionName(n Node) string {
+	return strings.TrimPrefix(n.attr("text"), "@")
+}
+
+// dateString formats the timestamp of a date node, given in milliseconds as string or number.
+func dateString(n Node) string {
+	var ms int64
+	switch v := n.Attrs["timestamp"].(type) {
+	case float64:
+		ms = int64(v)
+	case string:
+		if _, err := fmt.Sscan(v, &ms); err != nil {
+			return v
+		}
+	default:
+		return ""
+	}
+	return time.Unix(ms/1000, 0).UTC().Format("2006-01-02")
+}
+
+func prefixLines(s, prefix, emptyPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isBlock(n Node) bool {
+	switch n.Type {
+	case TypeParagraph, TypeHeading, TypeBulletList, TypeOrderedList, TypeCodeBlock,
+		TypeBlockquote, TypeRule, TypeTable, TypePanel:
+		return true
+	}
+	return false
+}
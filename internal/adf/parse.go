@@ -0,0 +1,523 @@
+package adf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	headingRegexp   = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	fenceRegexp     = regexp.MustCompile("^(`{3,}|~{3,})\\s*([^`\\s]*)")
+	ruleRegexp      = regexp.MustCompile(`^(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	listItemRegexp  = regexp.MustCompile(`^([-*+]|\d{1,9}[.)])(?:\s+|$)`)
+	tableSepRegexp  = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	breakTagRegexp  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	hardBreakSuffix = []string{"\\", "  "}
+)
+
+// FromMarkdown converts Markdown to a document. It supports paragraphs, headings, lists,
+// fenced code blocks, block quotes, rules and tables, inline emphasis, strike, code,
+// links, autolinks and mentions written as @[name](account id).
+func FromMarkdown(md string) *Node {
+	md = strings.ReplaceAll(strings.ReplaceAll(md, "\r\n", "\n"), "\t", "    ")
+	return Doc(parseBlocks(strings.Split(md, "\n"))...)
+}
+
+func parseBlocks(lines []string) []Node {
+	var blocks []Node
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case fenceRegexp.MatchString(trimmed):
+			var n Node
+			n, i = parseCodeBlock(lines, i)
+			blocks = append(blocks, n)
+		case headingRegexp.MatchString(trimmed):
+			m := headingRegexp.FindStringSubmatch(trimmed)
+			blocks = append(blocks, Node{
+				Type:    TypeHeading,
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: parseInline(m[2]),
+			})
+			i++
+		case ruleRegexp.MatchString(trimmed):
+			blocks = append(blocks, Node{Type: TypeRule})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(l, " "))
+			}
+			blocks = append(blocks, Node{Type: TypeBlockquote, Content: parseBlocks(quoted)})
+		case listItemRegexp.MatchString(trimmed):
+			var n Node
+			n, i = parseList(lines, i)
+			blocks = append(blocks, n)
+		case isTableStart(lines, i):
+			var n Node
+			n, i = parseTable(lines, i)
+			blocks = append(blocks, n)
+		default:
+			var n Node
+			n, i = parseParagraph(lines, i)
+			blocks = append(blocks, n)
+		}
+	}
+	return blocks
+}
+
+func parseCodeBlock(lines []string, i int) (Node, int) {
+	m := fenceRegexp.FindStringSubmatch(strings.TrimSpace(lines[i]))
+	fence := m[1]
+	n := Node{Type: TypeCodeBlock}
+	if m[2] != "" {
+		n.Attrs = map[string]interface{}{"language": m[2]}
+	}
+	var code []string
+	for i++; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		n.Content = []Node{{Type: TypeText, Text: text}}
+	}
+	return n, i
+}
+
+func parseParagraph(lines []string, i int) (Node, int) {
+	var text strings.Builder
+	for start := i; i < len(lines); i++ {
+		line := lines[i]
+		if i > start && interruptsParagraph(lines, i) {
+			break
+		}
+		content := strings.TrimSpace(line)
+		hardBreak := false
+		for _, suffix := range hardBreakSuffix {
+			if strings.HasSuffix(strings.TrimLeft(line, " "), suffix) && i+1 < len(lines) && !interruptsParagraph(lines, i+1) {
+				content = strings.TrimSpace(strings.TrimSuffix(content, "\\"))
+				hardBreak = true
+			}
+		}
+		text.WriteString(content)
+		if hardBreak {
+			text.WriteString("\n")
+		} else {
+			text.WriteString(" ")
+		}
+	}
+	return Node{Type: TypeParagraph, Content: parseInline(strings.TrimRight(text.String(), " \n"))}, i
+}
+
+func interruptsParagraph(lines []string, i int) bool {
+	t := strings.TrimSpace(lines[i])
+	return t == "" || fenceRegexp.MatchString(t) || headingRegexp.MatchString(t) || ruleRegexp.MatchString(t) ||
+		strings.HasPrefix(t, ">") || listItemRegexp.MatchString(t) || isTableStart(lines, i)
+}
+
+func parseList(lines []string, i int) (Node, int) {
+	first := strings.TrimSpace(lines[i])
+	marker := listItemRegexp.FindStringSubmatch(first)[1]
+	ordered := unicode.IsDigit(rune(marker[0]))
+	list := Node{Type: TypeBulletList}
+	if ordered {
+		list.Type = TypeOrderedList
+		if start, _ := strconv.Atoi(marker[:len(marker)-1]); start != 1 {
+			list.Attrs = map[string]interface{}{"order": start}
+		}
+	}
+	baseIndent := indentOf(lines[i])
+	for i < len(lines) {
+		line := lines[i]
+		t := strings.TrimSpace(line)
+		m := listItemRegexp.FindStringSubmatch(t)
+		if m == nil || indentOf(line) != baseIndent || unicode.IsDigit(rune(m[1][0])) != ordered {
+			break
+		}
+		contentIndent := baseIndent + len(listItemRegexp.FindString(t))
+		item := []string{strings.TrimPrefix(t, listItemRegexp.FindString(t))}
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				// a blank line continues the item only if indented content follows
+				if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && indentOf(lines[i+1]) >= contentIndent {
+					item = append(item, "")
+					continue
+				}
+				break
+			}
+			if indentOf(l) >= contentIndent || (indentOf(l) > baseIndent && listItemRegexp.MatchString(strings.TrimSpace(l))) {
+				item = append(item, dedent(l, contentIndent))
+				continue
+			}
+			if indentOf(l) > baseIndent || !interruptsParagraph(lines, i) && item[len(item)-1] != "" {
+				// lazy continuation of the item paragraph
+				item = append(item, strings.TrimSpace(l))
+				continue
+			}
+			break
+		}
+		list.Content = append(list.Content, Node{Type: TypeListItem, Content: parseBlocks(item)})
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) {
+			next := lines[i+1]
+			if m := listItemRegexp.FindStringSubmatch(strings.TrimSpace(next)); m != nil && indentOf(next) == baseIndent {
+				i++
+			}
+		}
+	}
+	return list, i
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func dedent(line string, n int) string {
+	if indentOf(line) < n {
+		return strings.TrimLeft(line, " ")
+	}
+	return line[n:]
+}
+
+func isTableStart(lines []string, i int) bool {
+	return strings.Contains(lines[i], "|") && i+1 < len(lines) &&
+		strings.Contains(lines[i+1], "-") && tableSepRegexp.MatchString(strings.TrimSpace(lines[i+1]))
+}
+
+func parseTable(lines []string, i int) (Node, int) {
+	table := Node{Type: TypeTable}
+	table.Content = append(table.Content, tableRow(lines[i], TypeTableHeader))
+	for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+		table.Content = append(table.Content, tableRow(lines[i], TypeTableCell))
+	}
+	return table, i
+}
+
+func tableRow(line, cellType string) Node {
+	row := Node{Type: TypeTableRow}
+	for _, cell := range splitCells(line) {
+		var content []Node
+		for _, part := range breakTagRegexp.Split(cell, -1) {
+			content = append(content, Node{Type: TypeParagraph, Content: parseInline(strings.TrimSpace(part))})
+		}
+		row.Content = append(row.Content, Node{Type: cellType, Content: content})
+	}
+	return row
+}
+
+// splitCells splits a table row at the pipes that are not escaped or inside code spans.
+func splitCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	code := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteString("|")
+			i++
+			continue
+		case line[i] == '`':
+			code = !code
+		case line[i] == '|' && !code:
+			cells = append(cells, cell.String())
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, cell.String())
+}
+
+func parseInline(s string) []Node {
+	p := &inlineParser{s: s}
+	return mergeText(p.parse(""))
+}
+
+type inlineParser struct {
+	s   string
+	pos int
+	// unclosed holds the spans without closing delimiter, so they are not parsed again
+	unclosed map[spanKey]bool
+}
+
+type spanKey struct {
+	pos   int
+	delim string
+}
+
+// parse reads inline nodes until the closing delimiter or the end of the text.
+func (p *inlineParser) parse(closing string) []Node {
+	var nodes []Node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, Node{Type: TypeText, Text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.s) {
+		rest := p.s[p.pos:]
+		if closing != "" && strings.HasPrefix(rest, closing) && p.closes(closing) {
+			flush()
+			p.pos += len(closing)
+			return nodes
+		}
+		if n, ok := p.special(); ok {
+			flush()
+			nodes = append(nodes, n...)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(rest)
+		if r == '\\' && size < len(rest) && strings.ContainsRune("\\`*_{}[]()#+-.!|~<>@", rune(rest[1])) {
+			text.WriteByte(rest[1])
+			p.pos += 2
+			continue
+		}
+		if r == '\n' {
+			flush()
+			nodes = append(nodes, Node{Type: TypeHardBreak})
+			p.pos++
+			continue
+		}
+		text.WriteRune(r)
+		p.pos += size
+	}
+	flush()
+	if closing != "" {
+		// unclosed delimiter, the caller treats it as text
+		return nil
+	}
+	return nodes
+}
+
+// closes reports whether the delimiter at the current position may close a span.
+func (p *inlineParser) closes(delim string) bool {
+	if p.pos == 0 || unicode.IsSpace(rune(p.s[p.pos-1])) {
+		return false
+	}
+	if delim[0] == '_' {
+		end := p.pos + len(delim)
+		return end >= len(p.s) || !isWordByte(p.s[end])
+	}
+	return true
+}
+
+func (p *inlineParser) special() ([]Node, bool) {
+	rest := p.s[p.pos:]
+	switch {
+	case rest[0] == '`':
+		return p.codeSpan()
+	case strings.HasPrefix(rest, "@["):
+		return p.mention()
+	case rest[0] == '[':
+		return p.link()
+	case rest[0] == '<':
+		return p.autolink()
+	}
+	for _, d := range []struct {
+		delim string
+		mark  string
+	}{{"**", MarkStrong}, {"__", MarkStrong}, {"~~", MarkStrike}, {"*", MarkEm}, {"_", MarkEm}} {
+		if strings.HasPrefix(rest, d.delim) {
+			return p.span(d.delim, d.mark)
+		}
+	}
+	return nil, false
+}
+
+func (p *inlineParser) span(delim, mark string) ([]Node, bool) {
+	start := p.pos
+	after := start + len(delim)
+	if after >= len(p.s) || unicode.IsSpace(rune(p.s[after])) {
+		return nil, false
+	}
+	if delim[0] == '_' && start > 0 && isWordByte(p.s[start-1]) {
+		return nil, false
+	}
+	if p.unclosed[spanKey{start, delim}] {
+		return nil, false
+	}
+	p.pos = after
+	inner := p.parse(delim)
+	if inner == nil {
+		if p.unclosed == nil {
+			p.unclosed = make(map[spanKey]bool)
+		}
+		p.unclosed[spanKey{start, delim}] = true
+		p.pos = start
+		return nil, false
+	}
+	return addMark(inner, Mark{Type: mark}), true
+}
+
+func (p *inlineParser) codeSpan() ([]Node, bool) {
+	rest := p.s[p.pos:]
+	fence := rest[:len(rest)-len(strings.TrimLeft(rest, "`"))]
+	end := strings.Index(rest[len(fence):], fence)
+	if end < 0 {
+		return nil, false
+	}
+	code := rest[len(fence) : len(fence)+end]
+	if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+		code = code[1 : len(code)-1]
+	}
+	p.pos += 2*len(fence) + end
+	return []Node{{Type: TypeText, Text: code, Marks: []Mark{{Type: MarkCode}}}}, true
+}
+
+// bracket returns the text in brackets and the target in parentheses following the current position.
+func (p *inlineParser) bracket(offset int) (string, string, int, bool) {
+	rest := p.s[p.pos+offset:]
+	depth := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if !strings.HasPrefix(rest[i+1:], "(") {
+				return "", "", 0, false
+			}
+			end := closingParen(rest[i+2:])
+			if end < 0 {
+				return "", "", 0, false
+			}
+			return rest[1:i], strings.TrimSpace(rest[i+2 : i+2+end]), offset + i + 3 + end, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// closingParen returns the index of the parenthesis closing a link target, parentheses
+// within the target must be balanced.
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func (p *inlineParser) mention() ([]Node, bool) {
+	name, id, n, ok := p.bracket(1)
+	if !ok || id == "" {
+		return nil, false
+	}
+	p.pos += n
+	return []Node{{Type: TypeMention, Attrs: map[string]interface{}{"id": id, "text": "@" + unescape(name)}}}, true
+}
+
+func (p *inlineParser) link() ([]Node, bool) {
+	text, href, n, ok := p.bracket(0)
+	if !ok {
+		return nil, false
+	}
+	p.pos += n
+	inner := mergeText((&inlineParser{s: text}).parse(""))
+	if len(inner) == 0 {
+		inner = []Node{{Type: TypeText, Text: href}}
+	}
+	return addMark(inner, Mark{Type: MarkLink, Attrs: map[string]interface{}{"href": href}}), true
+}
+
+func (p *inlineParser) autolink() ([]Node, bool) {
+	rest := p.s[p.pos:]
+	end := strings.Index(rest, ">")
+	if end < 0 {
+		return nil, false
+	}
+	href := rest[1:end]
+	if !strings.Contains(href, "://") && !strings.HasPrefix(href, "mailto:") || strings.ContainsAny(href, " <") {
+		return nil, false
+	}
+	p.pos += end + 1
+	return []Node{{Type: TypeText, Text: href, Marks: []Mark{{Type: MarkLink, Attrs: map[string]interface{}{"href": href}}}}}, true
+}
+
+// addMark adds the mark to the text nodes, code may only be combined with links.
+func addMark(nodes []Node, m Mark) []Node {
+	for i := range nodes {
+		n := &nodes[i]
+		if n.Type != TypeText || n.mark(m.Type) != nil || (n.mark(MarkCode) != nil && m.Type != MarkLink) {
+			continue
+		}
+		n.Marks = append(n.Marks, m)
+	}
+	return nodes
+}
+
+func mergeText(nodes []Node) []Node {
+	var merged []Node
+	for _, n := range nodes {
+		if l := len(merged); l > 0 && n.Type == TypeText && merged[l-1].Type == TypeText && sameMarks(merged[l-1].Marks, n.Marks) {
+			merged[l-1].Text += n.Text
+			continue
+		}
+		merged = append(merged, n)
+	}
+	return merged
+}
+
+func sameMarks(a, b []Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, m := range a {
+		found := false
+		for _, o := range b {
+			if m.Type == o.Type && m.attr("href") == o.attr("href") {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
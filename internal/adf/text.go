@@ -0,0 +1,96 @@
+package adf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToText renders a document as plain text. Links are followed by their target in
+// parentheses unless the link text is the target.
+func ToText(doc *Node) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimRight(textBlocks(doc.Content), "\n")
+}
+
+func textBlocks(blocks []Node) string {
+	var parts []string
+	for _, b := range blocks {
+		if s := textBlock(b); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func textBlock(n Node) string {
+	switch n.Type {
+	case TypeParagraph, TypeHeading, TypeCodeBlock:
+		return plainInline(n.Content)
+	case TypeBulletList, TypeOrderedList:
+		start := n.intAttr("order", 1)
+		var items []string
+		for i, item := range n.Content {
+			marker := "- "
+			if n.Type == TypeOrderedList {
+				marker = fmt.Sprintf("%d. ", start+i)
+			}
+			indent := strings.Repeat(" ", len(marker))
+			body := prefixLines(itemBlocks(item.Content, textBlock), indent, "")
+			items = append(items, marker+strings.TrimPrefix(body, indent))
+		}
+		return strings.Join(items, "\n")
+	case TypeBlockquote, TypePanel:
+		return prefixLines(textBlocks(n.Content), "> ", ">")
+	case TypeRule:
+		return "---"
+	case TypeTable:
+		var rows []string
+		for _, row := range n.Content {
+			var cells []string
+			for _, cell := range row.Content {
+				cells = append(cells, strings.ReplaceAll(textBlocks(cell.Content), "\n", " "))
+			}
+			rows = append(rows, strings.Join(cells, " | "))
+		}
+		return strings.Join(rows, "\n")
+	default:
+		if len(n.Content) > 0 && isBlock(n.Content[0]) {
+			return textBlocks(n.Content)
+		}
+		return plainInline([]Node{n})
+	}
+}
+
+func plainInline(nodes []Node) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case TypeText:
+			sb.WriteString(n.Text)
+			if link := n.mark(MarkLink); link != nil && link.attr("href") != n.Text {
+				sb.WriteString(" (" + link.attr("href") + ")")
+			}
+		case TypeHardBreak:
+			sb.WriteString("\n")
+		case TypeMention:
+			sb.WriteString("@" + mentionName(n))
+		case TypeEmoji:
+			if text := n.attr("text"); text != "" {
+				sb.WriteString(text)
+			} else {
+				sb.WriteString(n.attr("shortName"))
+			}
+		case TypeInlineCard:
+			sb.WriteString(n.attr("url"))
+		case TypeStatus:
+			sb.WriteString(n.attr("text"))
+		case TypeDate:
+			sb.WriteString(dateString(n))
+		default:
+			sb.WriteString(plainInline(n.Content))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+type User struct {
+	AccountId    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	AccountId string    `json:"accountId"`
+	User      string    `json:"user"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	BodyHash  string    `json:"bodyHash"`
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	PrevHash  string    `json:"prevHash"`
+	Hash      string    `json:"hash"`
+}
+
+func (r AuditRecord) computeHash() string {
+	r.Hash = ""
+	b, _ := json.Marshal(r)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+type AuditLog struct {
+	Path     string
+	mu       sync.Mutex
+	lastHash *string
+	// broken keeps the failure of the log, no further write may be sent after it
+	broken error
+}
+
+// Open verifies the hash chain of the audit log and checks that it can be continued, a
+// missing log is started.
+func (l *AuditLog) Open() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.open()
+}
+
+func (l *AuditLog) open() error {
+	records, err := l.Verify()
+	if err != nil && !os.IsNotExist(err) {
+		l.broken = err
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		l.broken = fmt.Errorf("Audit-Log %s ist nicht beschreibbar (%s)", l.Path, err)
+		return l.broken
+	}
+	_ = f.Close()
+	last := ""
+	if len(records) > 0 {
+		last = records[len(records)-1].Hash
+	}
+	l.lastHash = &last
+	return nil
+}
+
+func (l *AuditLog) failed() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.broken
+}
+
+func (l *AuditLog) Append(r AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.broken != nil {
+		return l.broken
+	}
+	if l.lastHash == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	r.PrevHash = *l.lastHash
+	r.Hash = r.computeHash()
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		l.broken = err
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	err = json.NewEncoder(f).Encode(r)
+	if err != nil {
+		l.broken = err
+		return err
+	}
+	l.lastHash = &r.Hash
+	return nil
+}
+
+func (l *AuditLog) Verify() ([]AuditRecord, error) {
+	f, err := os.Open(l.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	var records []AuditRecord
+	prevHash := ""
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; s.Scan(); n++ {
+		r := AuditRecord{}
+		err := json.Unmarshal(s.Bytes(), &r)
+		if err != nil {
+			return records, fmt.Errorf("Audit-Log %s Eintrag %d ist ungültig (%s)", l.Path, n, err)
+		}
+		if r.PrevHash != prevHash {
+			return records, fmt.Errorf("Audit-Log %s Eintrag %d ist nicht mit dem vorherigen Eintrag verkettet", l.Path, n)
+		}
+		if r.Hash != r.computeHash() {
+			return records, fmt.Errorf("Audit-Log %s Eintrag %d wurde verändert", l.Path, n)
+		}
+		records = append(records, r)
+		prevHash = r.Hash
+	}
+	return records, s.Err()
+}
+
+func (c *JiraRestClient) GetMyself() (*User, error) {
+	rel := &url.URL{Path: "/rest/api/3/myself"}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	user := &User{}
+	_, err = c.call(req, user)
+	return user, err
+}
+
+// EnableAudit records every write request in the audit log. The log is verified and the
+// Jira account is read once here, so no write is sent that cannot be recorded.
+func (c *JiraRestClient) EnableAudit(audit *AuditLog) error {
+	err := audit.Open()
+	if err != nil {
+		return err
+	}
+	user, err := c.GetMyself()
+	if err != nil {
+		return fmt.Errorf("Jira Benutzer für das Audit-Log kann nicht gelesen werden (%s)", err)
+	}
+	c.Audit = audit
+	c.auditUser = user
+	return nil
+}
+
+func (c *JiraRestClient) audit(req *http.Request, resp *http.Response, callErr error) error {
+	bodyHash := sha256.New()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			_, _ = io.Copy(bodyHash, body)
+			_ = body.Close()
+		}
+	}
+	r := AuditRecord{
+		Time:      time.Now().UTC(),
+		AccountId: c.auditUser.AccountId,
+		User:      c.auditUser.DisplayName,
+		Method:    req.Method,
+		Path:      req.URL.RequestURI(),
+		BodyHash:  hex.EncodeToString(bodyHash.Sum(nil)),
+	}
+	if resp != nil {
+		r.Status = resp.StatusCode
+	}
+	if callErr != nil {
+		r.Error = callErr.Error()
+	}
+	err := c.Audit.Append(r)
+	if err != nil {
+		return fmt.Errorf("Audit-Log %s kann %s %s nicht aufnehmen (%s)", c.Audit.Path, req.Method, r.Path, err)
+	}
+	return nil
+}
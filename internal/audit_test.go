@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJiraRestClient_Audit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method + " " + req.URL.Path {
+		case "GET /rest/api/3/myself":
+			rw.Write([]byte("{\"accountId\": \"5b10a\",\"displayName\": \"Max Mustermann\",\"emailAddress\": \"max@example.com\"}"))
+		case "GET /rest/api/3/project/DB":
+			rw.Write([]byte("{\"id\": \"10000\",\"key\": \"DB\"}"))
+		case "PUT /rest/api/3/version/10000":
+			rw.Write([]byte("{\"id\": \"10000\",\"name\": \"2021-07\"}"))
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	audit := &AuditLog{Path: filepath.Join(t.TempDir(), "audit.jsonl")}
+	u, _ := url.Parse(server.URL)
+	c, _ := CreateRestClient(nil, u)
+	if err := c.EnableAudit(audit); err != nil {
+		t.Fatalf("got: %v - want: no Error", err)
+	}
+	_, _ = c.GetProject("DB")
+	ver := Version{Id: "10000", Name: "2021-07", Released: true, ProjectId: 10000}
+	if err := c.UpdateVersion(ver); err != nil {
+		t.Fatalf("got: %v - want: no Error", err)
+	}
+	if err := c.CreateVersion(Version{Name: "2021-08", ProjectId: 10000}); err == nil {
+		t.Fatalf("got: no Error - want: Error")
+	}
+
+	records, err := audit.Verify()
+	if err != nil {
+		t.Fatalf("got: %v - want: no Error", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got: %d - want: 2", len(records))
+	}
+	req, _ := c.createRestRequest(&url.URL{Path: "/rest/api/3/version/10000"}, "PUT", ver)
+	body, _ := io.ReadAll(req.Body)
+	sum := sha256.Sum256(body)
+	r := records[0]
+	switch {
+	case r.AccountId != "5b10a" || r.User != "Max Mustermann":
+		t.Errorf("got: %s %s - want: 5b10a Max Mustermann", r.AccountId, r.User)
+	case r.Method != "PUT" || r.Path != "/rest/api/3/version/10000" || r.Status != http.StatusOK:
+		t.Errorf("got: %s %s %d - want: PUT /rest/api/3/version/10000 200", r.Method, r.Path, r.Status)
+	case r.BodyHash != hex.EncodeToString(sum[:]):
+		t.Errorf("got: %s - want: %s", r.BodyHash, hex.EncodeToString(sum[:]))
+	case r.PrevHash != "":
+		t.Errorf("got: %s - want: empty hash", r.PrevHash)
+	}
+	if records[1].PrevHash != r.Hash || records[1].Status != http.StatusBadRequest || records[1].Error == "" {
+		t.Errorf("got: %v - want: chained failed request", records[1])
+	}
+
+	audit2 := &AuditLog{Path: audit.Path}
+	if err := audit2.Append(AuditRecord{Method: "DELETE", Path: "/rest/api/3/version/10000"}); err != nil {
+		t.Fatalf("got: %v - want: no Error", err)
+	}
+	records, err = audit2.Verify()
+	if err != nil || len(records) != 3 || records[2].PrevHash != records[1].Hash {
+		t.Errorf("got: %v %v - want: 3 chained records", records, err)
+	}
+}
+
+func TestJiraRestClient_AuditFailures(t *testing.T) {
+	var writes int
+	myself := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/rest/api/3/myself":
+			rw.WriteHeader(myself)
+			rw.Write([]byte("{\"accountId\": \"5b10a\"}"))
+		default:
+			writes++
+			rw.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c, _ := CreateRestClient(nil, u)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := c.EnableAudit(&AuditLog{Path: path}); err == nil {
+		t.Errorf("got: no Error - want: Error for unknown Jira user")
+	}
+	myself = http.StatusOK
+	if err := c.EnableAudit(&AuditLog{Path: t.TempDir()}); err == nil {
+		t.Errorf("got: no Error - want: Error for unreadable audit log")
+	}
+	_ = os.WriteFile(path, []byte("{\"prevHash\": \"x\"}\n"), 0600)
+	if err := c.EnableAudit(&AuditLog{Path: path}); err == nil {
+		t.Errorf("got: no Error - want: Error for tampered audit log")
+	}
+
+	_ = os.Remove(path)
+	if err := c.EnableAudit(&AuditLog{Path: path}); err != nil {
+		t.Fatalf("got: %v - want: no Error", err)
+	}
+	_ = os.Remove(path)
+	_ = os.Mkdir(path, 0700)
+	if err := c.UpdateVersion(Version{Id: "10000", Name: "2021-07"}); err == nil {
+		t.Errorf("got: no Error - want: Error for unwritten audit record")
+	}
+	if err := c.UpdateVersion(Version{Id: "10000", Name: "2021-07"}); err == nil {
+		t.Errorf("got: no Error - want: Error for broken audit log")
+	}
+	if writes != 1 {
+		t.Errorf("got: %d writes - want: no write after the broken audit log", writes)
+	}
+}
+
+func TestAuditLog_Verify(t *testing.T) {
+	tests := []struct {
+		testcase string
+		tamper   func(lines []string) []string
+		err      string
+	}{
+		{
+			"unchanged",
+			func(lines []string) []string { return lines },
+			"",
+		},
+		{
+			"changed record",
+			func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "\"status\":200", "\"status\":204", 1)
+				return lines
+			},
+			"Eintrag 2 wurde verändert",
+		},
+		{
+			"removed record",
+			func(lines []string) []string { return append(lines[:1], lines[2:]...) },
+			"Eintrag 2 ist nicht mit dem vorherigen Eintrag verkettet",
+		},
+		{
+			"invalid record",
+			func(lines []string) []string { return append(lines, "{") },
+			"Eintrag 4 ist ungültig",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			audit := &AuditLog{Path: filepath.Join(t.TempDir(), "audit.jsonl")}
+			for _, m := range []string{"POST", "PUT", "DELETE"} {
+				_ = audit.Append(AuditRecord{Method: m, Path: "/rest/api/3/version", Status: http.StatusOK})
+			}
+			b, _ := os.ReadFile(audit.Path)
+			lines := tt.tamper(strings.Split(strings.TrimSpace(string(b)), "\n"))
+			_ = os.WriteFile(audit.Path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+			_, err := audit.Verify()
+			switch {
+			case err != nil && tt.err == "":
+				t.Errorf("got: %v - want: no Error", err)
+			case err == nil && tt.err != "":
+				t.Errorf("got: no Error - want: %s", tt.err)
+			case err != nil && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("got: %v - want: %s", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type CacheConfig struct {
+	Dir string `json:"dir"`
+	TTL string `json:"ttl"`
+}
+
+// invalidatedPaths lists the cached reads that are outdated by a write to a path.
+var invalidatedPaths = map[string][]string{
+	"/rest/api/3/version/":    {"/rest/api/3/project/", "/rest/api/3/version/"},
+	"/rest/api/3/version":     {"/rest/api/3/project/"},
+	"/rest/api/3/component/":  {"/rest/api/3/project/"},
+	"/rest/api/3/component":   {"/rest/api/3/project/"},
+	"/rest/api/3/issue/":      {"/rest/api/3/issue/", "/rest/api/3/search", "/rest/api/3/version/"},
+	"/rest/api/3/issue":       {"/rest/api/3/search", "/rest/api/3/version/"},
+	"/rest/api/3/issueLink":   {"/rest/api/3/issue/", "/rest/api/3/search"},
+	"/rest/agile/1.0/sprint":  {"/rest/agile/1.0/board/"},
+	"/rest/agile/1.0/sprint/": {"/rest/agile/1.0/board/", "/rest/api/3/search", "/rest/api/3/issue/"},
+}
+
+// Cache stores the responses of GET requests on disk. Entries younger than TTL
+// are used without a request, older entries are revalidated with If-None-Match.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+	now func() time.Time
+}
+
+type CacheEntry struct {
+	User   string    `json:"user"`
+	URL    string    `json:"url"`
+	ETag   string    `json:"etag,omitempty"`
+	Stored time.Time `json:"stored"`
+	Body   string    `json:"body"`
+}
+
+func NewCache(cfg CacheConfig) (*Cache, error) {
+	c := &Cache{Dir: cfg.Dir, now: time.Now}
+	if cfg.TTL != "" {
+		ttl, err := time.ParseDuration(cfg.TTL)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("Cache TTL %s ist ungültig", cfg.TTL)
+		}
+		c.TTL = ttl
+	}
+	if c.Dir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("Cache Verzeichnis kann nicht bestimmt werden (%s)", err)
+		}
+		c.Dir = filepath.Join(dir, "jiratool")
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("Cache Verzeichnis %s kann nicht angelegt werden (%s)", c.Dir, err)
+	}
+	return c, nil
+}
+
+func (c *Cache) Get(user, rawURL string) *CacheEntry {
+	data, err := os.ReadFile(c.path(user, rawURL))
+	if err != nil {
+		return nil
+	}
+	entry := &CacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil || entry.User != user || entry.URL != rawURL {
+		return nil
+	}
+	return entry
+}
+
+func (c *Cache) Fresh(entry *CacheEntry) bool {
+	return c.now().Sub(entry.Stored) < c.TTL
+}
+
+func (c *Cache) Put(entry *CacheEntry) error {
+	entry.Stored = c.now()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(entry.User, entry.URL), data, 0600)
+}
+
+// Invalidate removes the entries of all users that are outdated by a write to path.
+func (c *Cache) Invalidate(path string) error {
+	var prefixes []string
+	for p, invalidated := range invalidatedPaths {
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			prefixes = append(prefixes, invalidated...)
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		entry := &CacheEntry{}
+		if json.Unmarshal(data, entry) != nil {
+			continue
+		}
+		if u, err := url.Parse(entry.URL); err == nil && !hasAnyPrefix(u.Path, prefixes) {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) path(user, rawURL string) string {
+	key := sha256.Sum256([]byte(user + "\n" + rawURL))
+	return filepath.Join(c.Dir, hex.EncodeToString(key[:])+".json")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
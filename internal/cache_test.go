@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestJiraRestClient_Cache(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path+" "+req.Header.Get("If-None-Match"))
+		switch {
+		case req.Method != http.MethodGet:
+			_, _ = rw.Write([]byte(`{}`))
+		case req.Header.Get("If-None-Match") == `"v1"`:
+			rw.WriteHeader(http.StatusNotModified)
+		default:
+			rw.Header().Set("ETag", `"v1"`)
+			_, _ = rw.Write([]byte(`{"id":"10000","key":"PRJ","versions":[{"id":"10100","name":"1.0.0"}]}`))
+		}
+	}))
+	defer ts.Close()
+	cache, err := NewCache(CacheConfig{Dir: t.TempDir(), TTL: "10m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	u, _ := url.Parse(ts.URL)
+	c, _ := CreateRestClient(url.UserPassword("username", "apikey"), u)
+	c.Cache = cache
+
+	getProject := func() {
+		t.Helper()
+		prj, err := c.GetProject("PRJ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prj.Key != "PRJ" || len(prj.Versions) != 1 {
+			t.Errorf("got: %+v - want: project PRJ with 1 version", prj)
+		}
+	}
+	getProject()
+	getProject()
+	now = now.Add(time.Hour)
+	getProject()
+	if err := c.UpdateVersion(Version{Id: "10100", Name: "1.0.0", Released: true}); err != nil {
+		t.Fatal(err)
+	}
+	getProject()
+
+	c2, _ := CreateRestClient(url.UserPassword("other", "apikey"), u)
+	c2.Cache = cache
+	if _, err := c2.GetProject("PRJ"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /rest/api/3/project/PRJ ",
+		`GET /rest/api/3/project/PRJ "v1"`,
+		"PUT /rest/api/3/version/10100 ",
+		"GET /rest/api/3/project/PRJ ",
+		"GET /rest/api/3/project/PRJ ",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got: %q - want: %q", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("got: %q - want: %q", requests[i], want[i])
+		}
+	}
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	cache, err := NewCache(CacheConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls := []string{
+		"https://jira.example.com/rest/api/3/project/PRJ",
+		"https://jira.example.com/rest/api/3/search?jql=project+%3D+PRJ",
+		"https://jira.example.com/rest/api/3/issue/PRJ-1?fields=summary",
+	}
+	for _, u := range urls {
+		if err := cache.Put(&CacheEntry{User: "username", URL: u, Body: "{}"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		path   string
+		cached []bool
+	}{
+		{"/rest/api/3/myself", []bool{true, true, true}},
+		{"/rest/api/3/issue/PRJ-2", []bool{true, false, false}},
+		{"/rest/api/3/version", []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if err := cache.Invalidate(tt.path); err != nil {
+				t.Fatal(err)
+			}
+			for i, u := range urls {
+				if got := cache.Get("username", u) != nil; got != tt.cached[i] {
+					t.Errorf("got: %s cached %v - want: %v", u, got, tt.cached[i])
+				}
+			}
+		})
+	}
+	if _, err := NewCache(CacheConfig{Dir: t.TempDir(), TTL: "zehn Minuten"}); err == nil {
+		t.Errorf("got: no error - want: error for invalid TTL")
+	}
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+
+	scrubbed = "REDACTED"
+)
+
+var cassetteSkipHeaders = []string{"Set-Cookie", "Authorization", "X-Aaccountid", "Atl-Traceid", "X-Arequestid", "Date"}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	Status     string            `json:"status"`
+	StatusCode int               `json:"statusCode"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Recorder is a http.RoundTripper that records the requests of a JiraRestClient
+// to a cassette file or replays them from it without a Jira instance.
+type Recorder struct {
+	Mode      string
+	Path      string
+	Transport http.RoundTripper
+	// Scrub contains secrets, e.g. username and API key, that are replaced in recorded URLs and bodies
+	Scrub []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+func NewRecorder(path, mode string) (*Recorder, error) {
+	r := &Recorder{Mode: mode, Path: path, Transport: http.DefaultTransport}
+	switch mode {
+	case CassetteRecord:
+		return r, nil
+	case CassetteReplay:
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = *cassette
+		r.used = make([]bool, len(cassette.Interactions))
+		return r, nil
+	}
+	return nil, fmt.Errorf("Modus %s ist ungültig (%s, %s)", mode, CassetteRecord, CassetteReplay)
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Aufnahme %s kann nicht gelesen werden (%s)", path, err)
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("Aufnahme %s kann nicht gelesen werden (%s)", path, err)
+	}
+	return cassette, nil
+}
+
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Mode == CassetteReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	header := make(map[string]string)
+	for name := range resp.Header {
+		if !containsFold(cassetteSkipHeaders, name) {
+			header[name] = resp.Header.Get(name)
+		}
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.RequestURI()),
+			Body:   r.scrub(string(body)),
+		},
+		Response: CassetteResponse{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.scrub(string(respBody)),
+		},
+	})
+	// saved after every request, the run may end with os.Exit
+	return resp, r.cassette.Save(r.Path)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	uri := req.URL.RequestURI()
+	for n, i := range r.cassette.Interactions {
+		if r.used[n] || i.Request.Method != req.Method || i.Request.URL != r.scrub(uri) || i.Request.Body != r.scrub(string(body)) {
+			continue
+		}
+		r.used[n] = true
+		resp := &http.Response{
+			Status:        i.Response.Status,
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          io.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}
+		for name, value := range i.Response.Header {
+			resp.Header.Set(name, value)
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("Aufnahme %s enthält keine Antwort für %s %s", r.Path, req.Method, uri)
+}
+
+// Unused returns the recorded requests that were not replayed.
+func (r *Recorder) Unused() []CassetteRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []CassetteRequest
+	for n, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[n].Request)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.Scrub {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, scrubbed)
+		}
+	}
+	return s
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
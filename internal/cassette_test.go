@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createReplayClient(t *testing.T, cassette string) (*JiraRestClient, *Recorder) {
+	t.Helper()
+	rec, err := NewRecorder(filepath.Join("testdata", "cassettes", cassette), CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := CreateRestClient(url.UserPassword("username", "apikey"), &url.URL{Scheme: "https", Host: "test.atlassian.net"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HttpClient = &http.Client{Transport: rec}
+	return c, rec
+}
+
+func TestRecorderReplayProject(t *testing.T) {
+	c, rec := createReplayClient(t, "project.json")
+	prj, err := c.GetProject("SHOP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prj.Id != "10034" || len(prj.Versions) != 3 || len(prj.Components) != 2 {
+		t.Fatalf("got: %+v - want: project 10034 with 3 versions and 2 components", prj)
+	}
+	info, err := InspectVersion(prj, "1.0.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Version 1.0.0 in Projekt SHOP ist archiviert (released am 2021-01-15)"; info != want {
+		t.Errorf("got: %s - want: %s", info, want)
+	}
+	if ver := prj.Versions[1]; ver.StartDate == nil || *ver.StartDate != "2021-02-01" || *ver.UserReleaseDate != "01/Mar/21" {
+		t.Errorf("got: %+v - want: start date 2021-02-01 and user release date 01/Mar/21", ver)
+	}
+	comps, err := c.GetComponents("SHOP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comps[0].Name != "Backend" || comps[0].AssigneeType != "COMPONENT_LEAD" {
+		t.Errorf("got: %+v - want: component Backend with assignee type COMPONENT_LEAD", comps[0])
+	}
+	_, err = c.GetProject("NOPE")
+	if restErr, ok := err.(RestError); !ok || restErr.Status() != http.StatusNotFound {
+		t.Errorf("got: %v - want: RestError 404", err)
+	}
+	if unused := rec.Unused(); len(unused) > 0 {
+		t.Errorf("got: unused requests %v - want: none", unused)
+	}
+}
+
+func TestRecorderReplayRelease(t *testing.T) {
+	pc, _ := createReplayClient(t, "project.json")
+	prj, err := pc.GetProject("SHOP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, rec := createReplayClient(t, "release.json")
+	check, err := CheckRelease(prj, "1.1.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Version 1.1.0 in Projekt SHOP hat 2 offene Vorgänge:\n  SHOP-12 Warenkorb speichern (In Arbeit)\n  SHOP-14 Export als CSV (Zu erledigen)"
+	if check.String() != want {
+		t.Errorf("got: %s - want: %s", check, want)
+	}
+	if err := ReleaseVersion(prj, "1.1.0", "2021-03-05", c); err != nil {
+		t.Fatal(err)
+	}
+	if unused := rec.Unused(); len(unused) > 0 {
+		t.Errorf("got: unused requests %v - want: none", unused)
+	}
+	if err := ReleaseVersion(prj, "1.1.0", "2021-03-05", c); err == nil {
+		t.Errorf("got: no error - want: error for request missing in cassette")
+	}
+}
+
+func TestRecorderRecord(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		user, _, _ := req.BasicAuth()
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Set-Cookie", "atlassian.xsrf.token=secret")
+		_, _ = rw.Write([]byte(`{"id":"10000","key":"PRJ","description":"lead ` + user + `","versions":[]}`))
+	}))
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := NewRecorder(path, CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Scrub = []string{"username", "apikey"}
+	u, _ := url.Parse(ts.URL)
+	c, _ := CreateRestClient(url.UserPassword("username", "apikey"), u)
+	c.HttpClient = &http.Client{Transport: rec}
+	if _, err := c.GetProject("PRJ"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"username", "apikey", "Authorization", "xsrf"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("got: %s in cassette - want: scrubbed\n%s", secret, data)
+		}
+	}
+
+	replay, err := NewRecorder(path, CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HttpClient = &http.Client{Transport: replay}
+	prj, err := c.GetProject("PRJ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prj.Key != "PRJ" || prj.Description != "lead "+scrubbed {
+		t.Errorf("got: %+v - want: project PRJ with scrubbed description", prj)
+	}
+}
+
+func TestNewRecorder(t *testing.T) {
+	if _, err := NewRecorder("cassette.json", "rewind"); err == nil {
+		t.Errorf("got: no error - want: error for invalid mode")
+	}
+	if _, err := NewRecorder(filepath.Join("testdata", "cassettes", "missing.json"), CassetteReplay); err == nil {
+		t.Errorf("got: no error - want: error for missing cassette")
+	}
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"fmt"
+	"strings"
+)
+
+const (
+	VisibilityRole  = "role"
+	VisibilityGroup = "group"
+)
+
+type Comment struct {
+	Id         string      `json:"id,omitempty"`
+	Body       *adf.Node   `json:"body"`
+	Visibility *Visibility `json:"visibility,omitempty"`
+}
+
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ParseVisibility reads a visibility restriction written as role:<name> or group:<name>.
+func ParseVisibility(s string) (*Visibility, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	kv := strings.SplitN(s, ":", 2)
+	if len(kv) != 2 || strings.TrimSpace(kv[1]) == "" {
+		return nil, fmt.Errorf("Sichtbarkeit %s ist ungültig (role:<Rolle> oder group:<Gruppe>)", s)
+	}
+	t := strings.ToLower(strings.TrimSpace(kv[0]))
+	if t != VisibilityRole && t != VisibilityGroup {
+		return nil, fmt.Errorf("Sichtbarkeit %s ist ungültig (role:<Rolle> oder group:<Gruppe>)", s)
+	}
+	return &Visibility{Type: t, Value: strings.TrimSpace(kv[1])}, nil
+}
+
+// CommentIssues adds a comment to the issues. The body is Markdown with the placeholders
+// of issue templates and {issue} for the issue key, {project} is the project of the issue.
+// The placeholders are filled after parsing, their values are not read as Markdown.
+func CommentIssues(issueKeys []string, body string, values TemplateValues, vis *Visibility, c RestClient) []IssueResult {
+	doc := adf.FromMarkdown(body)
+	var results []IssueResult
+	for _, k := range issueKeys {
+		v := values
+		v.Project = IssueProjectKey(k)
+		text := adf.ReplaceText(adf.ReplaceText(*doc, v.replacer()), strings.NewReplacer("{issue}", k))
+		err := c.AddComment(k, Comment{Body: &text, Visibility: vis})
+		results = append(results, IssueResult{Key: k, Err: err})
+	}
+	return results
+}
+
+// CommentChanges lists the comments to add for the confirmation.
+func CommentChanges(issueKeys []string, vis *Visibility) []Change {
+	var changes []Change
+	for _, k := range issueKeys {
+		ch := Change{Project: IssueProjectKey(k), Object: "Vorgang " + k, Operation: OpComment}
+		if vis != nil {
+			ch.State = fmt.Sprintf("sichtbar für %s %s", vis.Type, vis.Value)
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"reflect"
+	"testing"
+)
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *Visibility
+		err   bool
+	}{
+		{"", nil, false},
+		{"role:Developers", &Visibility{Type: VisibilityRole, Value: "Developers"}, false},
+		{"Group: jira-users ", &Visibility{Type: VisibilityGroup, Value: "jira-users"}, false},
+		{"user:max", nil, true},
+		{"role:", nil, true},
+		{"Developers", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			vis, err := ParseVisibility(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("got: %v - want error: %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(vis, tt.want) {
+				t.Errorf("got: %v - want: %v", vis, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommentIssues(t *testing.T) {
+	c := &TestRestClient{}
+	vis := &Visibility{Type: VisibilityRole, Value: "Developers"}
+	values := TemplateValues{Version: "*2021-07*", ReleaseDate: "2021-07-06"}
+	results := CommentIssues([]string{"PRJ-1", "DB-2"}, "Ausgeliefert in **{version}** am {releaseDate} ({project}, [{issue}](https://jira/browse/{issue}))", values, vis, c)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("got: %s %v - want: no error", r.Key, r.Err)
+		}
+	}
+	for key, want := range map[string]string{
+		"PRJ-1": "Ausgeliefert in **\\*2021-07\\*** am 2021-07-06 (PRJ, [PRJ-1](https://jira/browse/PRJ-1))",
+		"DB-2":  "Ausgeliefert in **\\*2021-07\\*** am 2021-07-06 (DB, [DB-2](https://jira/browse/DB-2))",
+	} {
+		comments := c.comments[key]
+		if len(comments) != 1 || adf.ToMarkdown(comments[0].Body) != want || comments[0].Visibility != vis {
+			t.Errorf("got: %+v - want: %s visible for role Developers", comments, want)
+		}
+	}
+	changes := CommentChanges([]string{"PRJ-1"}, vis)
+	want := []Change{{Project: "PRJ", Object: "Vorgang PRJ-1", Operation: OpComment, State: "sichtbar für role Developers"}}
+	if !reflect.DeepEqual(changes, want) || !Destructive(changes) {
+		t.Errorf("got: %v - want: %v", changes, want)
+	}
+}
@@ -0,0 +1,92 @@
+package internal
+
+import "strings"
+
+type VersionComparison struct {
+	Projects []string
+	Versions []string
+	versions map[string]map[string]Version
+}
+
+func CompareVersions(prjs []*Project) *VersionComparison {
+	vc := &VersionComparison{versions: make(map[string]map[string]Version)}
+	for _, prj := range prjs {
+		vc.Projects = append(vc.Projects, prj.Key)
+		for _, v := range prj.Versions {
+			if _, ok := vc.versions[v.Name]; !ok {
+				vc.Versions = append(vc.Versions, v.Name)
+				vc.versions[v.Name] = make(map[string]Version)
+			}
+			vc.versions[v.Name][prj.Key] = v
+		}
+	}
+	return vc
+}
+
+func (vc *VersionComparison) Mismatch(verName string) bool {
+	prjVersions := vc.versions[verName]
+	if len(prjVersions) != len(vc.Projects) {
+		return true
+	}
+	var first *Version
+	for _, pk := range vc.Projects {
+		v := prjVersions[pk]
+		if first == nil {
+			first = &v
+			continue
+		}
+		if v.Released != first.Released || v.Archived != first.Archived || !equalDate(v.ReleaseDate, first.ReleaseDate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (vc *VersionComparison) Mismatches() []string {
+	var names []string
+	for _, name := range vc.Versions {
+		if vc.Mismatch(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (vc *VersionComparison) Table() ([]string, [][]string) {
+	header := append(append([]string{"version"}, vc.Projects...), "abweichung")
+	var rows [][]string
+	for _, name := range vc.Versions {
+		row := []string{name}
+		for _, pk := range vc.Projects {
+			v, ok := vc.versions[name][pk]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, versionCell(v))
+		}
+		if vc.Mismatch(name) {
+			row = append(row, "ja")
+		} else {
+			row = append(row, "")
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+func versionCell(ver Version) string {
+	var state []string
+	if ver.Released {
+		state = append(state, "released")
+	} else {
+		state = append(state, "offen")
+	}
+	if ver.ReleaseDate != nil {
+		state = append(state, *ver.ReleaseDate)
+	}
+	if ver.Archived {
+		state = append(state, "archiviert")
+	}
+	return strings.Join(state, " ")
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	relDate := "2021-07-06"
+	otherDate := "2021-07-09"
+	prjs := []*Project{
+		{
+			Key: "DB",
+			Versions: []Version{
+				{Name: "2021-06", Released: true, ReleaseDate: &relDate, Archived: true},
+				{Name: "2021-07", Released: true, ReleaseDate: &relDate},
+				{Name: "2021-08"},
+			},
+		},
+		{
+			Key: "MN",
+			Versions: []Version{
+				{Name: "2021-06", Released: true, ReleaseDate: &relDate, Archived: true},
+				{Name: "2021-07", Released: true, ReleaseDate: &otherDate},
+				{Name: "2021-09"},
+			},
+		},
+	}
+	vc := CompareVersions(prjs)
+	header, rows := vc.Table()
+	wantHeader := []string{"version", "DB", "MN", "abweichung"}
+	wantRows := [][]string{
+		{"2021-06", "released 2021-07-06 archiviert", "released 2021-07-06 archiviert", ""},
+		{"2021-07", "released 2021-07-06", "released 2021-07-09", "ja"},
+		{"2021-08", "offen", "-", "ja"},
+		{"2021-09", "-", "offen", "ja"},
+	}
+	if !reflect.DeepEqual(header, wantHeader) {
+		t.Errorf("got: %v - want: %v", header, wantHeader)
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("got: %v - want: %v", rows, wantRows)
+	}
+	if !reflect.DeepEqual(vc.Mismatches(), []string{"2021-07", "2021-08", "2021-09"}) {
+		t.Errorf("got: %v - want: %v", vc.Mismatches(), []string{"2021-07", "2021-08", "2021-09"})
+	}
+}
+
+func TestVersionComparison_Mismatch(t *testing.T) {
+	relDate := "2021-07-06"
+	tests := []struct {
+		testcase string
+		versions []Version
+		mismatch bool
+	}{
+		{"same state", []Version{{Name: "2021-07"}, {Name: "2021-07"}}, false},
+		{"different release state", []Version{{Name: "2021-07", Released: true}, {Name: "2021-07"}}, true},
+		{"different archive state", []Version{{Name: "2021-07", Archived: true}, {Name: "2021-07"}}, true},
+		{"missing release date", []Version{{Name: "2021-07", ReleaseDate: &relDate}, {Name: "2021-07"}}, true},
+		{"missing version", []Version{{Name: "2021-07"}, {Name: "2021-08"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			vc := CompareVersions([]*Project{
+				{Key: "DB", Versions: tt.versions[:1]},
+				{Key: "MN", Versions: tt.versions[1:]},
+			})
+			if vc.Mismatch("2021-07") != tt.mismatch {
+				t.Errorf("got: %v - want: %v", vc.Mismatch("2021-07"), tt.mismatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+var assigneeTypes = []string{"PROJECT_DEFAULT", "COMPONENT_LEAD", "PROJECT_LEAD", "UNASSIGNED"}
+
+type Component struct {
+	Id            string `json:"id,omitempty"`
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	LeadAccountId string `json:"leadAccountId,omitempty"`
+	AssigneeType  string `json:"assigneeType,omitempty"`
+	Project       string `json:"project,omitempty"`
+	ProjectId     int    `json:"projectId,omitempty"`
+}
+
+func CreateComponent(prj *Project, compName, lead, assigneeType string, c RestClient) error {
+	if _, err := getComponent(prj, compName); err == nil {
+		return fmt.Errorf("Komponente %s ist in Projekt %s bereits vorhanden", compName, prj.Key)
+	}
+	if err := validateAssigneeType(assigneeType); err != nil {
+		return err
+	}
+	comp := Component{
+		Name:          compName,
+		LeadAccountId: lead,
+		AssigneeType:  assigneeType,
+		Project:       prj.Key,
+	}
+	return c.CreateComponent(comp)
+}
+
+func UpdateComponent(prj *Project, compName, lead, assigneeType string, c RestClient) error {
+	comp, err := getComponent(prj, compName)
+	if err != nil {
+		return err
+	}
+	if err := validateAssigneeType(assigneeType); err != nil {
+		return err
+	}
+	if lead != "" {
+		comp.LeadAccountId = lead
+	}
+	if assigneeType != "" {
+		comp.AssigneeType = assigneeType
+	}
+	return c.UpdateComponent(*comp)
+}
+
+func DeleteComponent(prj *Project, compName, moveTo string, c RestClient) error {
+	comp, err := getComponent(prj, compName)
+	if err != nil {
+		return err
+	}
+	var moveToId string
+	if moveTo != "" {
+		target, err := getComponent(prj, moveTo)
+		if err != nil {
+			return err
+		}
+		moveToId = target.Id
+	}
+	return c.DeleteComponent(comp.Id, moveToId)
+}
+
+func getComponent(prj *Project, compName string) (*Component, error) {
+	for _, comp := range prj.Components {
+		if comp.Name == compName {
+			return &comp, nil
+		}
+	}
+	return nil, fmt.Errorf("Komponente %s ist in Projekt %s nicht vorhanden", compName, prj.Key)
+}
+
+func validateAssigneeType(assigneeType string) error {
+	if assigneeType == "" {
+		return nil
+	}
+	for _, t := range assigneeTypes {
+		if t == assigneeType {
+			return nil
+		}
+	}
+	return fmt.Errorf("Standardbearbeiter %s ist ungültig (%s)", assigneeType, strings.Join(assigneeTypes, ", "))
+}
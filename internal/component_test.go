@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateComponent(t *testing.T) {
+	tests := []struct {
+		testcase      string
+		project       Project
+		componentName string
+		lead          string
+		assigneeType  string
+		components    []Component
+		err           bool
+	}{
+		{
+			"create component",
+			Project{Key: "PRJ"},
+			"backend",
+			"5b10ac8d82e05b22cc7d4ef5",
+			"COMPONENT_LEAD",
+			[]Component{{Name: "backend", LeadAccountId: "5b10ac8d82e05b22cc7d4ef5", AssigneeType: "COMPONENT_LEAD", Project: "PRJ"}},
+			false,
+		},
+		{
+			"component already present",
+			Project{Key: "PRJ", Components: []Component{{Id: "10000", Name: "backend"}}},
+			"backend",
+			"",
+			"",
+			nil,
+			true,
+		},
+		{
+			"invalid assignee type",
+			Project{Key: "PRJ"},
+			"backend",
+			"",
+			"NOBODY",
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &TestRestClient{}
+			err := CreateComponent(&tt.project, tt.componentName, tt.lead, tt.assigneeType, c)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case !reflect.DeepEqual(c.components, tt.components):
+				t.Errorf("got: %v - want: %v", c.components, tt.components)
+			}
+		})
+	}
+}
+
+func TestUpdateComponent(t *testing.T) {
+	comps := []Component{{Id: "10000", Name: "backend", LeadAccountId: "5b10a", AssigneeType: "PROJECT_DEFAULT", Project: "PRJ"}}
+	tests := []struct {
+		testcase      string
+		componentName string
+		lead          string
+		assigneeType  string
+		component     Component
+		err           bool
+	}{
+		{
+			"update lead",
+			"backend",
+			"5b10b",
+			"",
+			Component{Id: "10000", Name: "backend", LeadAccountId: "5b10b", AssigneeType: "PROJECT_DEFAULT", Project: "PRJ"},
+			false,
+		},
+		{
+			"update assignee type",
+			"backend",
+			"",
+			"COMPONENT_LEAD",
+			Component{Id: "10000", Name: "backend", LeadAccountId: "5b10a", AssigneeType: "COMPONENT_LEAD", Project: "PRJ"},
+			false,
+		},
+		{
+			"component not present",
+			"frontend",
+			"5b10b",
+			"",
+			comps[0],
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &TestRestClient{components: append([]Component{}, comps...)}
+			prj := Project{Key: "PRJ", Components: comps}
+			err := UpdateComponent(&prj, tt.componentName, tt.lead, tt.assigneeType, c)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case !reflect.DeepEqual(c.components[0], tt.component):
+				t.Errorf("got: %v - want: %v", c.components[0], tt.component)
+			}
+		})
+	}
+}
+
+func TestDeleteComponent(t *testing.T) {
+	comps := []Component{{Id: "10000", Name: "backend"}, {Id: "10001", Name: "frontend"}}
+	tests := []struct {
+		testcase      string
+		componentName string
+		moveTo        string
+		moveToId      string
+		err           bool
+	}{
+		{"delete component", "backend", "", "", false},
+		{"delete component and move issues", "backend", "frontend", "10001", false},
+		{"component not present", "api", "", "", true},
+		{"move target not present", "backend", "api", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &deleteComponentRestClient{}
+			prj := Project{Key: "PRJ", Components: comps}
+			err := DeleteComponent(&prj, tt.componentName, tt.moveTo, c)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case err == nil && (c.compId != "10000" || c.moveIssuesTo != tt.moveToId):
+				t.Errorf("got: %s %s - want: 10000 %s", c.compId, c.moveIssuesTo, tt.moveToId)
+			}
+		})
+	}
+}
+
+type deleteComponentRestClient struct {
+	TestRestClient
+	compId       string
+	moveIssuesTo string
+}
+
+func (c *deleteComponentRestClient) DeleteComponent(compId, moveIssuesTo string) error {
+	c.compId = compId
+	c.moveIssuesTo = moveIssuesTo
+	return nil
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Config struct {
+	Groups    map[string][]string `json:"groups"`
+	Journal   string              `json:"journal"`
+	Audit     string              `json:"audit"`
+	Transport TransportConfig     `json:"transport"`
+	Cache     *CacheConfig        `json:"cache"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	return ReadConfig(f)
+}
+
+func ReadConfig(r io.Reader) (*Config, error) {
+	cfg := &Config{}
+	err := json.NewDecoder(r).Decode(cfg)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Konfiguration kann nicht gelesen werden (%s)", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) GroupNames() []string {
+	var names []string
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) ExpandProjects(keys []string) []string {
+	var prjKeys []string
+	seen := make(map[string]bool)
+	add := func(k string) {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[k] {
+			return
+		}
+		seen[k] = true
+		prjKeys = append(prjKeys, k)
+	}
+	for _, k := range keys {
+		grp, ok := c.Groups[strings.TrimSpace(k)]
+		if !ok {
+			add(k)
+			continue
+		}
+		for _, gk := range grp {
+			add(gk)
+		}
+	}
+	return prjKeys
+}
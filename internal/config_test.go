@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	tests := []struct {
+		testcase string
+		config   string
+		groups   map[string][]string
+		err      bool
+	}{
+		{
+			"config with groups",
+			"{\"groups\": {\"backend\": [\"API\", \"DB\", \"AUTH\"], \"frontend\": [\"WEB\"]}}",
+			map[string][]string{"backend": {"API", "DB", "AUTH"}, "frontend": {"WEB"}},
+			false,
+		},
+		{
+			"empty config",
+			"",
+			nil,
+			false,
+		},
+		{
+			"invalid config",
+			"backend: [API, DB, AUTH]",
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			cfg, err := ReadConfig(strings.NewReader(tt.config))
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no Error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no Error - want: Error")
+			case err == nil && !reflect.DeepEqual(cfg.Groups, tt.groups):
+				t.Errorf("got: %v - want: %v", cfg.Groups, tt.groups)
+			}
+		})
+	}
+}
+
+func TestConfig_ExpandProjects(t *testing.T) {
+	cfg := &Config{Groups: map[string][]string{
+		"backend":  {"API", "DB", "AUTH"},
+		"platform": {"DB", "OPS"},
+	}}
+	tests := []struct {
+		testcase string
+		keys     []string
+		projects []string
+	}{
+		{
+			"projects only",
+			[]string{"DB", "MN"},
+			[]string{"DB", "MN"},
+		},
+		{
+			"group only",
+			[]string{"backend"},
+			[]string{"API", "DB", "AUTH"},
+		},
+		{
+			"overlapping groups and projects",
+			[]string{"AUTH", "backend", "platform", "OPS"},
+			[]string{"AUTH", "API", "DB", "OPS"},
+		},
+		{
+			"empty keys",
+			[]string{"", " "},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			projects := cfg.ExpandProjects(tt.keys)
+			if !reflect.DeepEqual(projects, tt.projects) {
+				t.Errorf("got: %v - want: %v", projects, tt.projects)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package fakejira
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const agilePrefix = "/rest/agile/1.0/"
+
+type board struct {
+	id      int
+	name    string
+	typ     string
+	project *project
+}
+
+func (s *Server) AddBoard(prjKey, name, typ string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.project(prjKey)
+	if prj == nil {
+		return 0, fmt.Errorf("project %s not found", prjKey)
+	}
+	b := &board{id: len(s.boards) + 1, name: name, typ: typ, project: prj}
+	s.boards = append(s.boards, b)
+	return b.id, nil
+}
+
+func (s *Server) AddSprint(sprint internal.Sprint) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.board(sprint.OriginBoardId) == nil {
+		return 0, fmt.Errorf("board %d not found", sprint.OriginBoardId)
+	}
+	if sprint.State == "" {
+		sprint.State = internal.SprintFuture
+	}
+	sprint.Id, _ = strconv.Atoi(s.newId())
+	s.sprints = append(s.sprints, &sprint)
+	return sprint.Id, nil
+}
+
+// SetIssueSprint puts an issue into a sprint, 0 moves it to the backlog.
+func (s *Server) SetIssueSprint(issueKey string, sprintId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.issue(issueKey)
+	if i == nil {
+		return fmt.Errorf("issue %s not found", issueKey)
+	}
+	i.sprint = sprintId
+	return nil
+}
+
+func (s *Server) Sprint(sprintId int) (*internal.Sprint, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sprint := s.sprint(sprintId)
+	if sprint == nil {
+		return nil, nil, false
+	}
+	var keys []string
+	for _, i := range s.issues {
+		if i.sprint == sprintId {
+			keys = append(keys, i.key)
+		}
+	}
+	result := *sprint
+	return &result, keys, true
+}
+
+func (s *Server) serveAgile(rw http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, agilePrefix), "/"), "/")
+	route := req.Method + " " + path[0]
+	switch {
+	case route == "GET board" && len(path) == 1:
+		s.getBoards(rw, req)
+	case route == "GET board" && len(path) == 3 && path[2] == "sprint":
+		s.getSprints(rw, req, path[1])
+	case route == "POST sprint" && len(path) == 1:
+		s.postSprint(rw, req)
+	case route == "POST sprint" && len(path) == 2:
+		s.updateSprint(rw, req, path[1])
+	case route == "POST sprint" && len(path) == 3 && path[2] == "issue":
+		s.moveIssuesToSprint(rw, req, path[1])
+	default:
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("No resource found for %s %s", req.Method, req.URL.Path))
+	}
+}
+
+func (s *Server) getBoards(rw http.ResponseWriter, req *http.Request) {
+	prjKey := req.URL.Query().Get("projectKeyOrId")
+	values := []map[string]interface{}{}
+	for _, b := range s.boards {
+		if prjKey != "" && b.project.key != prjKey && b.project.id != prjKey {
+			continue
+		}
+		prjId, _ := strconv.Atoi(b.project.id)
+		values = append(values, map[string]interface{}{
+			"id":       b.id,
+			"name":     b.name,
+			"type":     b.typ,
+			"location": map[string]interface{}{"projectId": prjId, "projectKey": b.project.key, "projectName": b.project.name},
+		})
+	}
+	writeJSON(rw, http.StatusOK, agilePage(values))
+}
+
+func (s *Server) getSprints(rw http.ResponseWriter, req *http.Request, boardId string) {
+	id, _ := strconv.Atoi(boardId)
+	b := s.board(id)
+	if b == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Board %s does not exist or you do not have permission to see it.", boardId))
+		return
+	}
+	if b.typ != "scrum" {
+		writeError(rw, http.StatusBadRequest, "The board does not support sprints")
+		return
+	}
+	values := []internal.Sprint{}
+	for _, sprint := range s.sprints {
+		if sprint.OriginBoardId == id {
+			values = append(values, *sprint)
+		}
+	}
+	writeJSON(rw, http.StatusOK, agilePage(values))
+}
+
+func (s *Server) postSprint(rw http.ResponseWriter, req *http.Request) {
+	sprint := internal.Sprint{}
+	if !readJSON(rw, req, &sprint) {
+		return
+	}
+	switch b := s.board(sprint.OriginBoardId); {
+	case sprint.Name == "":
+		writeFieldError(rw, http.StatusBadRequest, "name", "Sprint name is required.")
+		return
+	case b == nil || b.typ != "scrum":
+		writeFieldError(rw, http.StatusBadRequest, "originBoardId", "Board does not exist or does not support sprints.")
+		return
+	}
+	sprint.Id, _ = strconv.Atoi(s.newId())
+	sprint.State = internal.SprintFuture
+	s.sprints = append(s.sprints, &sprint)
+	writeJSON(rw, http.StatusCreated, sprint)
+}
+
+func (s *Server) updateSprint(rw http.ResponseWriter, req *http.Request, sprintId string) {
+	id, _ := strconv.Atoi(sprintId)
+	sprint := s.sprint(id)
+	if sprint == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Sprint %s does not exist.", sprintId))
+		return
+	}
+	update := internal.Sprint{}
+	if !readJSON(rw, req, &update) {
+		return
+	}
+	if update.Name != "" {
+		sprint.Name = update.Name
+	}
+	if update.StartDate != nil {
+		sprint.StartDate = update.StartDate
+	}
+	if update.EndDate != nil {
+		sprint.EndDate = update.EndDate
+	}
+	switch {
+	case update.State == "" || update.State == sprint.State:
+	case update.State == internal.SprintActive && sprint.State == internal.SprintFuture:
+		if sprint.StartDate == nil || sprint.EndDate == nil {
+			writeError(rw, http.StatusBadRequest, "Sprint must have a start and end date to be started.")
+			return
+		}
+		for _, other := range s.sprints {
+			if other.OriginBoardId == sprint.OriginBoardId && other.State == internal.SprintActive {
+				writeError(rw, http.StatusBadRequest, "A sprint is already active on this board.")
+				return
+			}
+		}
+		sprint.State = internal.SprintActive
+	case update.State == internal.SprintClosed && sprint.State == internal.SprintActive:
+		for _, i := range s.issues {
+			if i.sprint == id && !i.resolved() {
+				i.sprint = 0
+			}
+		}
+		sprint.State = internal.SprintClosed
+		sprint.CompleteDate = sprint.EndDate
+	default:
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Sprint cannot change state from %s to %s.", sprint.State, update.State))
+		return
+	}
+	writeJSON(rw, http.StatusOK, sprint)
+}
+
+func (s *Server) moveIssuesToSprint(rw http.ResponseWriter, req *http.Request, sprintId string) {
+	id, _ := strconv.Atoi(sprintId)
+	sprint := s.sprint(id)
+	if sprint == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Sprint %s does not exist.", sprintId))
+		return
+	}
+	if sprint.State == internal.SprintClosed {
+		writeError(rw, http.StatusBadRequest, "Issues cannot be moved to a closed sprint.")
+		return
+	}
+	body := struct {
+		Issues []string `json:"issues"`
+	}{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	if len(body.Issues) > 50 {
+		writeError(rw, http.StatusBadRequest, "At most 50 issues can be moved in one request.")
+		return
+	}
+	var issues []*issue
+	for _, k := range body.Issues {
+		i := s.issue(k)
+		if i == nil {
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("Issue %s does not exist.", k))
+			return
+		}
+		issues = append(issues, i)
+	}
+	for _, i := range issues {
+		i.sprint = id
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) board(boardId int) *board {
+	for _, b := range s.boards {
+		if b.id == boardId {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) sprint(sprintId int) *internal.Sprint {
+	for _, sprint := range s.sprints {
+		if sprint.Id == sprintId {
+			return sprint
+		}
+	}
+	return nil
+}
+
+func agilePage(values interface{}) map[string]interface{} {
+	return map[string]interface{}{"startAt": 0, "maxResults": 50, "isLast": true, "values": values}
+}
@@ -0,0 +1,73 @@
+package fakejira
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+var (
+	projectRoles = []string{"Administrators", "Developers", "Users"}
+	groups       = []string{"jira-administrators", "jira-software-users"}
+)
+
+type comment struct {
+	id         string
+	body       interface{}
+	visibility *internal.Visibility
+}
+
+// Comments returns the comments of an issue as a REST client reads them.
+func (s *Server) Comments(issueKey string) ([]internal.Comment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.issue(issueKey)
+	if i == nil {
+		return nil, false
+	}
+	var comments []internal.Comment
+	for _, cm := range i.comments {
+		data, err := json.Marshal(map[string]interface{}{"id": cm.id, "body": cm.body, "visibility": cm.visibility})
+		if err != nil {
+			return nil, false
+		}
+		c := internal.Comment{}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, false
+		}
+		comments = append(comments, c)
+	}
+	return comments, true
+}
+
+func (s *Server) postComment(rw http.ResponseWriter, req *http.Request, issueKey string) {
+	i := s.issue(issueKey)
+	if i == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	body := struct {
+		Body       map[string]interface{} `json:"body"`
+		Visibility *internal.Visibility   `json:"visibility"`
+	}{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	if body.Body["type"] != "doc" {
+		writeFieldError(rw, http.StatusBadRequest, "comment", "Comment body is not valid!")
+		return
+	}
+	if v := body.Visibility; v != nil {
+		switch {
+		case v.Type == internal.VisibilityRole && contains(projectRoles, v.Value):
+		case v.Type == internal.VisibilityGroup && contains(groups, v.Value):
+		default:
+			writeFieldError(rw, http.StatusBadRequest, "commentLevel", fmt.Sprintf("You are currently not a member of the project role or group: %s.", v.Value))
+			return
+		}
+	}
+	cm := comment{id: s.newId(), body: body.Body, visibility: body.Visibility}
+	i.comments = append(i.comments, cm)
+	writeJSON(rw, http.StatusCreated, map[string]interface{}{"id": cm.id, "body": cm.body, "visibility": cm.visibility})
+}
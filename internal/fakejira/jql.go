@@ -0,0 +1,338 @@
+package fakejira
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var orderByRegexp = regexp.MustCompile(`(?i)\s*\border\s+by\b.*$`)
+
+type condition interface {
+	match(s *Server, i *issue) bool
+}
+
+type andCondition []condition
+
+func (c andCondition) match(s *Server, i *issue) bool {
+	for _, cond := range c {
+		if !cond.match(s, i) {
+			return false
+		}
+	}
+	return true
+}
+
+type orCondition []condition
+
+func (c orCondition) match(s *Server, i *issue) bool {
+	for _, cond := range c {
+		if cond.match(s, i) {
+			return true
+		}
+	}
+	return false
+}
+
+type notCondition struct {
+	cond condition
+}
+
+func (c notCondition) match(s *Server, i *issue) bool {
+	return !c.cond.match(s, i)
+}
+
+type clause struct {
+	field    string
+	operator string
+	values   []string
+}
+
+func (c clause) match(s *Server, i *issue) bool {
+	values := s.fieldValues(i, c.field)
+	switch c.operator {
+	case "=", "in":
+		return matchAny(values, c.values)
+	case "!=", "not in":
+		return !matchAny(values, c.values)
+	case "~":
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), strings.ToLower(c.values[0])) {
+				return true
+			}
+		}
+		return false
+	case "is":
+		return len(values) == 0
+	case "is not":
+		return len(values) > 0
+	}
+	return false
+}
+
+func (s *Server) fieldValues(i *issue, field string) []string {
+	switch field {
+	case "project":
+		return []string{i.project.key, i.project.id, i.project.name}
+	case "key", "issuekey", "id":
+		return []string{i.key, i.id}
+	case "summary", "text":
+		return []string{i.summary}
+	case "issuetype", "type":
+		return []string{i.issueType}
+	case "status":
+		return []string{i.status}
+	case "statuscategory":
+		return []string{statusCategories[i.status], statusCategoryNames[statusCategories[i.status]]}
+	case "resolution":
+		if i.resolved() {
+			return []string{"Done"}
+		}
+		return nil
+	case "labels":
+		return i.labels
+	case "fixversion":
+		var values []string
+		for _, ref := range s.versionRefs(i) {
+			values = append(values, ref["id"].(string), ref["name"].(string))
+		}
+		return values
+	case "sprint":
+		if sprint := s.sprint(i.sprint); sprint != nil {
+			return []string{strconv.Itoa(sprint.Id), sprint.Name}
+		}
+		return nil
+	case "component":
+		var values []string
+		for _, ref := range s.componentRefs(i) {
+			values = append(values, ref["id"].(string), ref["name"].(string))
+		}
+		return values
+	}
+	return nil
+}
+
+func matchAny(values, targets []string) bool {
+	for _, t := range targets {
+		if strings.EqualFold(t, "unresolved") && len(values) == 0 {
+			return true
+		}
+		for _, v := range values {
+			if strings.EqualFold(v, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var jqlFields = map[string]bool{
+	"project": true, "key": true, "issuekey": true, "id": true, "summary": true, "text": true,
+	"issuetype": true, "type": true, "status": true, "statuscategory": true, "resolution": true,
+	"labels": true, "fixversion": true, "component": true, "sprint": true,
+}
+
+type jqlParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseJQL(jql string) (condition, error) {
+	jql = orderByRegexp.ReplaceAllString(jql, "")
+	tokens, err := tokenize(jql)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	p := &jqlParser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("Error in the JQL Query: Expecting either 'OR' or 'AND' but got '%s'.", p.tokens[p.pos])
+	}
+	return cond, nil
+}
+
+func (p *jqlParser) parseOr() (condition, error) {
+	cond, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orCondition{cond}
+	for p.accept("or") {
+		cond, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, cond)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *jqlParser) parseAnd() (condition, error) {
+	cond, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	and := andCondition{cond}
+	for p.accept("and") {
+		cond, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, cond)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *jqlParser) parseNot() (condition, error) {
+	if p.accept("not") {
+		cond, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{cond}, nil
+	}
+	if p.accept("(") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("Error in the JQL Query: Expecting ')' before the end of the query.")
+		}
+		return cond, nil
+	}
+	return p.parseClause()
+}
+
+func (p *jqlParser) parseClause() (condition, error) {
+	field, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("Error in the JQL Query: Expecting a field name before the end of the query.")
+	}
+	field = strings.ToLower(field)
+	if !jqlFields[field] {
+		return nil, fmt.Errorf("Field '%s' does not exist or you do not have permission to view it.", field)
+	}
+	c := clause{field: field}
+	switch {
+	case p.accept("="):
+		c.operator = "="
+	case p.accept("!="):
+		c.operator = "!="
+	case p.accept("~"):
+		c.operator = "~"
+	case p.accept("in"):
+		c.operator = "in"
+	case p.accept("not"):
+		if !p.accept("in") {
+			return nil, fmt.Errorf("Error in the JQL Query: Expecting 'IN' after 'NOT'.")
+		}
+		c.operator = "not in"
+	case p.accept("is"):
+		c.operator = "is"
+		if p.accept("not") {
+			c.operator = "is not"
+		}
+		if !p.accept("empty") && !p.accept("null") {
+			return nil, fmt.Errorf("Error in the JQL Query: Expecting 'EMPTY' after 'IS'.")
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("Error in the JQL Query: Expecting operator after field '%s'.", field)
+	}
+	if c.operator == "in" || c.operator == "not in" {
+		if !p.accept("(") {
+			return nil, fmt.Errorf("Error in the JQL Query: Expecting '(' after '%s'.", strings.ToUpper(c.operator))
+		}
+		for {
+			v, ok := p.value()
+			if !ok {
+				return nil, fmt.Errorf("Error in the JQL Query: Expecting a value in the list.")
+			}
+			c.values = append(c.values, v)
+			if p.accept(")") {
+				return c, nil
+			}
+			if !p.accept(",") {
+				return nil, fmt.Errorf("Error in the JQL Query: Expecting ',' or ')' in the list.")
+			}
+		}
+	}
+	v, ok := p.value()
+	if !ok {
+		return nil, fmt.Errorf("Error in the JQL Query: Expecting a value after '%s'.", c.operator)
+	}
+	c.values = []string{v}
+	return c, nil
+}
+
+func (p *jqlParser) value() (string, bool) {
+	v, ok := p.next()
+	if !ok || v == "(" || v == ")" || v == "," {
+		return "", false
+	}
+	return strings.Trim(v, "\"'"), true
+}
+
+func (p *jqlParser) accept(token string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], token) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jqlParser) next() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	p.pos++
+	return p.tokens[p.pos-1], true
+}
+
+func tokenize(jql string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(jql); {
+		ch := jql[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(' || ch == ')' || ch == ',' || ch == '=' || ch == '~':
+			tokens = append(tokens, string(ch))
+			i++
+		case ch == '!' && i+1 < len(jql) && jql[i+1] == '=':
+			tokens = append(tokens, "!=")
+			i += 2
+		case ch == '"' || ch == '\'':
+			end := strings.IndexByte(jql[i+1:], ch)
+			if end < 0 {
+				return nil, fmt.Errorf("Error in the JQL Query: The quoted string has not been completed.")
+			}
+			tokens = append(tokens, jql[i:i+end+2])
+			i += end + 2
+		default:
+			start := i
+			for i < len(jql) && !strings.ContainsRune(" \t\n\r(),=~!\"'", rune(jql[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("Error in the JQL Query: The character '%c' is a reserved JQL character.", ch)
+			}
+			tokens = append(tokens, jql[start:i])
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,76 @@
+package fakejira
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var linkTypes = []internal.IssueLinkType{
+	{Id: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+	{Id: "10001", Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+	{Id: "10002", Name: "Duplicate", Inward: "is duplicated by", Outward: "duplicates"},
+	{Id: "10003", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+}
+
+// link reads inward outward.Type.Outward outward, e.g. DEMO-3 blocks REL-1.
+type link struct {
+	id      string
+	typ     internal.IssueLinkType
+	inward  string
+	outward string
+}
+
+func (s *Server) getLinkTypes(rw http.ResponseWriter) {
+	writeJSON(rw, http.StatusOK, map[string]interface{}{"issueLinkTypes": linkTypes})
+}
+
+func (s *Server) postLink(rw http.ResponseWriter, req *http.Request) {
+	body := internal.IssueLink{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	var typ *internal.IssueLinkType
+	for i, lt := range linkTypes {
+		if strings.EqualFold(lt.Name, body.Type.Name) || lt.Id == body.Type.Id {
+			typ = &linkTypes[i]
+		}
+	}
+	if typ == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("No issue link type with name '%s' found.", body.Type.Name))
+		return
+	}
+	if body.InwardIssue == nil || body.OutwardIssue == nil || s.issue(body.InwardIssue.Key) == nil || s.issue(body.OutwardIssue.Key) == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	s.links = append(s.links, &link{id: s.newId(), typ: *typ, inward: body.InwardIssue.Key, outward: body.OutwardIssue.Key})
+	rw.WriteHeader(http.StatusCreated)
+}
+
+// issueLinks lists the links of an issue the way Jira shows them on both issues.
+func (s *Server) issueLinks(i *issue) []map[string]interface{} {
+	links := []map[string]interface{}{}
+	for _, l := range s.links {
+		var other, side string
+		switch i.key {
+		case l.inward:
+			other, side = l.outward, "outwardIssue"
+		case l.outward:
+			other, side = l.inward, "inwardIssue"
+		default:
+			continue
+		}
+		ref := map[string]interface{}{"key": other}
+		if o := s.issue(other); o != nil {
+			ref["id"] = o.id
+			ref["fields"] = map[string]interface{}{
+				"summary": o.summary,
+				"status":  map[string]interface{}{"name": o.status},
+			}
+		}
+		links = append(links, map[string]interface{}{"id": l.id, "type": l.typ, side: ref})
+	}
+	return links
+}
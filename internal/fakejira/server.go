@@ -0,0 +1,943 @@
+package fakejira
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jiraTimeLayout is the format of date-time fields of issues
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+const (
+	StatusToDo       = "To Do"
+	StatusInProgress = "In Progress"
+	StatusReady      = "Ready for Release"
+	StatusDone       = "Done"
+)
+
+var statusCategories = map[string]string{
+	StatusToDo:       "new",
+	StatusInProgress: "indeterminate",
+	StatusReady:      "indeterminate",
+	StatusDone:       "done",
+}
+
+var statusCategoryNames = map[string]string{
+	"new":           "To Do",
+	"indeterminate": "In Progress",
+	"done":          "Done",
+}
+
+type project struct {
+	id          string
+	key         string
+	name        string
+	description string
+	versions    []internal.Version
+	components  []internal.Component
+}
+
+type issue struct {
+	id          string
+	key         string
+	project     *project
+	issueType   string
+	summary     string
+	description interface{}
+	status      string
+	labels      []string
+	fixVersions []string
+	components  []string
+	sprint      int
+	parent      string
+	comments    []comment
+	created     time.Time
+	// resolutionDate is set by transitions to a done status
+	resolutionDate time.Time
+	custom         map[string]interface{}
+}
+
+type Server struct {
+	Username string
+	Token    string
+	Account  internal.User
+
+	mu       sync.Mutex
+	nextId   int
+	projects []*project
+	issues   []*issue
+	boards   []*board
+	sprints  []*internal.Sprint
+	links    []*link
+}
+
+func NewServer() *Server {
+	return &Server{
+		nextId: 10000,
+		Account: internal.User{
+			AccountId:    "5b10ac8d82e05b22cc7d4ef5",
+			DisplayName:  "Fake Jira",
+			EmailAddress: "fake@example.com",
+		},
+	}
+}
+
+func (s *Server) AddProject(key, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projects = append(s.projects, &project{id: s.newId(), key: key, name: name})
+}
+
+func (s *Server) AddVersion(prjKey string, ver internal.Version) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.project(prjKey)
+	if prj == nil {
+		return "", fmt.Errorf("project %s not found", prjKey)
+	}
+	ver.ProjectId, _ = strconv.Atoi(prj.id)
+	ver, err := s.createVersion(ver)
+	return ver.Id, err
+}
+
+func (s *Server) AddComponent(prjKey string, comp internal.Component) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	comp.Project = prjKey
+	comp, err := s.createComponent(comp)
+	return comp.Id, err
+}
+
+func (s *Server) AddIssue(prjKey, issueType, summary, status string, fixVersions ...string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.project(prjKey)
+	if prj == nil {
+		return "", fmt.Errorf("project %s not found", prjKey)
+	}
+	if _, ok := statusCategories[status]; !ok {
+		return "", fmt.Errorf("status %s not found", status)
+	}
+	i := &issue{
+		id:        s.newId(),
+		key:       fmt.Sprintf("%s-%d", prj.key, s.issueCount(prj)+1),
+		project:   prj,
+		issueType: issueType,
+		summary:   summary,
+		status:    status,
+		created:   time.Now(),
+	}
+	if i.resolved() {
+		i.resolutionDate = i.created
+	}
+	for _, name := range fixVersions {
+		ver := findVersion(prj, name)
+		if ver == nil {
+			return "", fmt.Errorf("version %s not found in project %s", name, prjKey)
+		}
+		i.fixVersions = append(i.fixVersions, ver.Id)
+	}
+	s.issues = append(s.issues, i)
+	return i.key, nil
+}
+
+// SetIssueField sets a custom field of an issue, e.g. the story points.
+func (s *Server) SetIssueField(issueKey, name string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.issue(issueKey)
+	if i == nil {
+		return fmt.Errorf("issue %s not found", issueKey)
+	}
+	i.setCustom(name, value)
+	return nil
+}
+
+// SetIssueDates sets when an issue was created and resolved, resolved is ignored for unresolved issues.
+func (s *Server) SetIssueDates(issueKey string, created, resolved time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.issue(issueKey)
+	if i == nil {
+		return fmt.Errorf("issue %s not found", issueKey)
+	}
+	i.created = created
+	if i.resolved() {
+		i.resolutionDate = resolved
+	}
+	return nil
+}
+
+func (s *Server) Project(prjKey string) (*internal.Project, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.project(prjKey)
+	if prj == nil {
+		return nil, false
+	}
+	return prj.toProject(), true
+}
+
+func (s *Server) Issue(issueKey string) (*internal.Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.issue(issueKey)
+	if i == nil {
+		return nil, false
+	}
+	// round trip through JSON so callers see the same field types as a REST client
+	data, err := json.Marshal(s.toIssue(i, nil))
+	if err != nil {
+		return nil, false
+	}
+	issue := &internal.Issue{}
+	if err := json.Unmarshal(data, issue); err != nil {
+		return nil, false
+	}
+	return issue, true
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if s.Username != "" || s.Token != "" {
+		user, token, ok := req.BasicAuth()
+		if !ok || user != s.Username || token != s.Token {
+			writeError(rw, http.StatusUnauthorized, "Client must be authenticated to access this resource.")
+			return
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasPrefix(req.URL.Path, agilePrefix) {
+		s.serveAgile(rw, req)
+		return
+	}
+	path := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/rest/api/3"), "/"), "/")
+	route := req.Method + " " + path[0]
+	switch {
+	case route == "GET myself" && len(path) == 1:
+		writeJSON(rw, http.StatusOK, s.Account)
+	case route == "GET project" && len(path) == 2:
+		s.getProject(rw, path[1])
+	case route == "GET project" && len(path) == 3 && path[2] == "components":
+		s.getComponents(rw, path[1])
+	case route == "POST version" && len(path) == 1:
+		s.postVersion(rw, req)
+	case route == "PUT version" && len(path) == 2:
+		s.putVersion(rw, req, path[1])
+	case route == "DELETE version" && len(path) == 2:
+		s.deleteVersion(rw, path[1])
+	case route == "POST version" && len(path) == 3 && path[2] == "move":
+		s.moveVersion(rw, req, path[1])
+	case route == "GET version" && len(path) == 3 && path[2] == "relatedIssueCounts":
+		s.relatedIssueCounts(rw, path[1])
+	case route == "GET version" && len(path) == 3 && path[2] == "unresolvedIssueCount":
+		s.unresolvedIssueCount(rw, path[1])
+	case route == "POST component" && len(path) == 1:
+		s.postComponent(rw, req)
+	case route == "PUT component" && len(path) == 2:
+		s.putComponent(rw, req, path[1])
+	case route == "DELETE component" && len(path) == 2:
+		s.deleteComponent(rw, req, path[1])
+	case route == "GET search" && len(path) == 1:
+		s.search(rw, req)
+	case route == "GET issueLinkType" && len(path) == 1:
+		s.getLinkTypes(rw)
+	case route == "POST issueLink" && len(path) == 1:
+		s.postLink(rw, req)
+	case route == "POST issue" && len(path) == 1:
+		s.postIssue(rw, req)
+	case route == "GET issue" && len(path) == 2:
+		s.getIssue(rw, req, path[1])
+	case route == "PUT issue" && len(path) == 2:
+		s.putIssue(rw, req, path[1])
+	case route == "POST issue" && len(path) == 3 && path[2] == "comment":
+		s.postComment(rw, req, path[1])
+	case route == "GET issue" && len(path) == 3 && path[2] == "transitions":
+		s.getTransitions(rw, req, path[1])
+	case route == "POST issue" && len(path) == 3 && path[2] == "transitions":
+		s.postTransition(rw, req, path[1])
+	default:
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("No resource found for %s %s", req.Method, req.URL.Path))
+	}
+}
+
+func (s *Server) getProject(rw http.ResponseWriter, prjKey string) {
+	prj := s.project(prjKey)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", prjKey))
+		return
+	}
+	writeJSON(rw, http.StatusOK, prj.toProject())
+}
+
+func (s *Server) getComponents(rw http.ResponseWriter, prjKey string) {
+	prj := s.project(prjKey)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", prjKey))
+		return
+	}
+	writeJSON(rw, http.StatusOK, append([]internal.Component{}, prj.components...))
+}
+
+func (s *Server) postVersion(rw http.ResponseWriter, req *http.Request) {
+	ver := internal.Version{}
+	if !readJSON(rw, req, &ver) {
+		return
+	}
+	ver, err := s.createVersion(ver)
+	if err != nil {
+		writeFieldError(rw, http.StatusBadRequest, "name", err.Error())
+		return
+	}
+	writeJSON(rw, http.StatusCreated, ver)
+}
+
+func (s *Server) createVersion(ver internal.Version) (internal.Version, error) {
+	prj := s.project(strconv.Itoa(ver.ProjectId))
+	switch {
+	case prj == nil:
+		return ver, fmt.Errorf("The project %d does not exist.", ver.ProjectId)
+	case strings.TrimSpace(ver.Name) == "":
+		return ver, fmt.Errorf("You must specify a valid version name")
+	case findVersion(prj, ver.Name) != nil:
+		return ver, fmt.Errorf("A version with this name already exists in this project.")
+	}
+	ver.Id = s.newId()
+	ver.UserReleaseDate = nil
+	prj.versions = append(prj.versions, ver)
+	return ver, nil
+}
+
+func (s *Server) putVersion(rw http.ResponseWriter, req *http.Request, verId string) {
+	prj, idx := s.version(verId)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", verId))
+		return
+	}
+	ver := prj.versions[idx]
+	upd := internal.Version{}
+	if !readJSON(rw, req, &upd) {
+		return
+	}
+	if upd.Name != "" && upd.Name != ver.Name {
+		if findVersion(prj, upd.Name) != nil {
+			writeFieldError(rw, http.StatusBadRequest, "name", "A version with this name already exists in this project.")
+			return
+		}
+		ver.Name = upd.Name
+	}
+	ver.Released = upd.Released
+	ver.Archived = upd.Archived
+	ver.ReleaseDate = upd.ReleaseDate
+	if upd.StartDate != nil {
+		ver.StartDate = upd.StartDate
+	}
+	prj.versions[idx] = ver
+	writeJSON(rw, http.StatusOK, ver)
+}
+
+func (s *Server) deleteVersion(rw http.ResponseWriter, verId string) {
+	prj, idx := s.version(verId)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", verId))
+		return
+	}
+	prj.versions = append(prj.versions[:idx], prj.versions[idx+1:]...)
+	for _, i := range s.issues {
+		i.fixVersions = remove(i.fixVersions, verId)
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) moveVersion(rw http.ResponseWriter, req *http.Request, verId string) {
+	prj, idx := s.version(verId)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", verId))
+		return
+	}
+	body := map[string]string{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	ver := prj.versions[idx]
+	versions := append(append([]internal.Version{}, prj.versions[:idx]...), prj.versions[idx+1:]...)
+	var pos int
+	switch body["position"] {
+	case "First":
+		pos = 0
+	case "Last":
+		pos = len(versions)
+	case "Earlier":
+		pos = idx - 1
+		if pos < 0 {
+			pos = 0
+		}
+	case "Later":
+		pos = idx + 1
+		if pos > len(versions) {
+			pos = len(versions)
+		}
+	default:
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Invalid position '%s'", body["position"]))
+		return
+	}
+	prj.versions = append(versions[:pos], append([]internal.Version{ver}, versions[pos:]...)...)
+	writeJSON(rw, http.StatusOK, ver)
+}
+
+func (s *Server) relatedIssueCounts(rw http.ResponseWriter, verId string) {
+	if prj, _ := s.version(verId); prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", verId))
+		return
+	}
+	fixed := 0
+	for _, i := range s.issues {
+		if contains(i.fixVersions, verId) {
+			fixed++
+		}
+	}
+	writeJSON(rw, http.StatusOK, internal.VersionRelatedIssueCounts{IssuesFixedCount: fixed})
+}
+
+func (s *Server) unresolvedIssueCount(rw http.ResponseWriter, verId string) {
+	if prj, _ := s.version(verId); prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", verId))
+		return
+	}
+	count := internal.VersionUnresolvedIssueCount{}
+	for _, i := range s.issues {
+		if contains(i.fixVersions, verId) {
+			count.IssuesCount++
+			if !i.resolved() {
+				count.IssuesUnresolvedCount++
+			}
+		}
+	}
+	writeJSON(rw, http.StatusOK, count)
+}
+
+func (s *Server) postComponent(rw http.ResponseWriter, req *http.Request) {
+	comp := internal.Component{}
+	if !readJSON(rw, req, &comp) {
+		return
+	}
+	comp, err := s.createComponent(comp)
+	if err != nil {
+		writeFieldError(rw, http.StatusBadRequest, "name", err.Error())
+		return
+	}
+	writeJSON(rw, http.StatusCreated, comp)
+}
+
+func (s *Server) createComponent(comp internal.Component) (internal.Component, error) {
+	prj := s.project(comp.Project)
+	switch {
+	case prj == nil:
+		return comp, fmt.Errorf("The project %s does not exist.", comp.Project)
+	case strings.TrimSpace(comp.Name) == "":
+		return comp, fmt.Errorf("The component name specified is invalid - cannot be empty.")
+	case findComponent(prj, comp.Name) != nil:
+		return comp, fmt.Errorf("A component with the name %s already exists in this project.", comp.Name)
+	}
+	comp.Id = s.newId()
+	comp.ProjectId, _ = strconv.Atoi(prj.id)
+	if comp.AssigneeType == "" {
+		comp.AssigneeType = "PROJECT_DEFAULT"
+	}
+	prj.components = append(prj.components, comp)
+	return comp, nil
+}
+
+func (s *Server) putComponent(rw http.ResponseWriter, req *http.Request, compId string) {
+	prj, idx := s.component(compId)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("The component with id %s does not exist.", compId))
+		return
+	}
+	upd := internal.Component{}
+	if !readJSON(rw, req, &upd) {
+		return
+	}
+	comp := prj.components[idx]
+	if upd.Name != "" {
+		comp.Name = upd.Name
+	}
+	if upd.Description != "" {
+		comp.Description = upd.Description
+	}
+	if upd.LeadAccountId != "" {
+		comp.LeadAccountId = upd.LeadAccountId
+	}
+	if upd.AssigneeType != "" {
+		comp.AssigneeType = upd.AssigneeType
+	}
+	prj.components[idx] = comp
+	writeJSON(rw, http.StatusOK, comp)
+}
+
+func (s *Server) deleteComponent(rw http.ResponseWriter, req *http.Request, compId string) {
+	prj, idx := s.component(compId)
+	if prj == nil {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf("The component with id %s does not exist.", compId))
+		return
+	}
+	moveTo := req.URL.Query().Get("moveIssuesTo")
+	if moveTo != "" {
+		if target, _ := s.component(moveTo); target != prj {
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("The component with id %s does not exist.", moveTo))
+			return
+		}
+	}
+	prj.components = append(prj.components[:idx], prj.components[idx+1:]...)
+	for _, i := range s.issues {
+		if contains(i.components, compId) {
+			i.components = remove(i.components, compId)
+			if moveTo != "" && !contains(i.components, moveTo) {
+				i.components = append(i.components, moveTo)
+			}
+		}
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) search(rw http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	cond, err := parseJQL(q.Get("jql"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+	startAt, _ := strconv.Atoi(q.Get("startAt"))
+	maxResults, err := strconv.Atoi(q.Get("maxResults"))
+	if err != nil || maxResults <= 0 || maxResults > 100 {
+		maxResults = 50
+	}
+	var fields []string
+	if q.Get("fields") != "" {
+		fields = strings.Split(q.Get("fields"), ",")
+	}
+	res := internal.SearchResult{StartAt: startAt, MaxResults: maxResults, Issues: []internal.Issue{}}
+	for _, i := range s.issues {
+		if cond != nil && !cond.match(s, i) {
+			continue
+		}
+		if res.Total >= startAt && len(res.Issues) < maxResults {
+			res.Issues = append(res.Issues, *s.toIssue(i, fields))
+		}
+		res.Total++
+	}
+	writeJSON(rw, http.StatusOK, res)
+}
+
+func (s *Server) getIssue(rw http.ResponseWriter, req *http.Request, issueKey string) {
+	i := s.issue(issueKey)
+	if i == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	var fields []string
+	if f := req.URL.Query().Get("fields"); f != "" {
+		fields = strings.Split(f, ",")
+	}
+	writeJSON(rw, http.StatusOK, s.toIssue(i, fields))
+}
+
+func (s *Server) postIssue(rw http.ResponseWriter, req *http.Request) {
+	body := internal.IssueUpdate{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	ref := func(name, key string) string {
+		m, _ := body.Fields[name].(map[string]interface{})
+		v, _ := m[key].(string)
+		return v
+	}
+	prj := s.project(ref("project", "key") + ref("project", "id"))
+	if prj == nil {
+		writeFieldError(rw, http.StatusBadRequest, "project", "Specify a valid project ID or key")
+		return
+	}
+	i := &issue{project: prj, issueType: ref("issuetype", "name"), status: StatusToDo, created: time.Now()}
+	if i.issueType == "" {
+		writeFieldError(rw, http.StatusBadRequest, "issuetype", "Specify an issue type")
+		return
+	}
+	if parentKey := ref("parent", "key"); parentKey != "" {
+		parent := s.issue(parentKey)
+		if parent == nil || parent.project != prj || parent.parent != "" {
+			writeFieldError(rw, http.StatusBadRequest, "parent", "Could not find issue by id or key.")
+			return
+		}
+		i.parent = parent.key
+	}
+	if (i.parent != "") != isSubTaskType(i.issueType) {
+		writeFieldError(rw, http.StatusBadRequest, "issuetype", "The issue type selected is invalid.")
+		return
+	}
+	if _, ok := body.Fields["summary"]; !ok {
+		writeFieldError(rw, http.StatusBadRequest, "summary", "You must specify a summary of the issue.")
+		return
+	}
+	for name, value := range body.Fields {
+		if name == "project" || name == "issuetype" || name == "parent" {
+			continue
+		}
+		err := s.setField(i, name, value)
+		if err != nil {
+			writeFieldError(rw, http.StatusBadRequest, name, err.Error())
+			return
+		}
+	}
+	i.id = s.newId()
+	i.key = fmt.Sprintf("%s-%d", prj.key, s.issueCount(prj)+1)
+	s.issues = append(s.issues, i)
+	writeJSON(rw, http.StatusCreated, map[string]string{"id": i.id, "key": i.key, "self": "/rest/api/3/issue/" + i.id})
+}
+
+func isSubTaskType(issueType string) bool {
+	return strings.EqualFold(issueType, "Sub-task") || strings.EqualFold(issueType, "Subtask")
+}
+
+func (s *Server) putIssue(rw http.ResponseWriter, req *http.Request, issueKey string) {
+	i := s.issue(issueKey)
+	if i == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	upd := internal.IssueUpdate{}
+	if !readJSON(rw, req, &upd) {
+		return
+	}
+	edited := *i
+	for name, value := range upd.Fields {
+		err := s.setField(&edited, name, value)
+		if err != nil {
+			writeFieldError(rw, http.StatusBadRequest, name, err.Error())
+			return
+		}
+	}
+	for name, ops := range upd.Update {
+		for _, op := range ops {
+			err := s.updateField(&edited, name, op)
+			if err != nil {
+				writeFieldError(rw, http.StatusBadRequest, name, err.Error())
+				return
+			}
+		}
+	}
+	*i = edited
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setField(i *issue, name string, value interface{}) error {
+	switch name {
+	case "summary":
+		summary, ok := value.(string)
+		if !ok || strings.TrimSpace(summary) == "" {
+			return fmt.Errorf("You must specify a summary of the issue.")
+		}
+		i.summary = summary
+	case "description":
+		i.description = value
+	case "labels", "fixVersions", "components":
+		values, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("Field '%s' must be a list", name)
+		}
+		var ids []string
+		for _, v := range values {
+			id, err := s.fieldValueId(i, name, v)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		i.setValues(name, ids)
+	default:
+		if !strings.HasPrefix(name, "customfield_") {
+			return fmt.Errorf("Field '%s' cannot be set. It is not on the appropriate screen, or unknown.", name)
+		}
+		i.setCustom(name, value)
+	}
+	return nil
+}
+
+func (s *Server) updateField(i *issue, name string, op internal.FieldOperation) error {
+	if op.Set != nil {
+		return s.setField(i, name, op.Set)
+	}
+	if name != "labels" && name != "fixVersions" && name != "components" {
+		return fmt.Errorf("Field '%s' cannot be updated with add or remove.", name)
+	}
+	values := i.values(name)
+	if op.Add != nil {
+		id, err := s.fieldValueId(i, name, op.Add)
+		if err != nil {
+			return err
+		}
+		if !contains(values, id) {
+			values = append(values, id)
+		}
+	}
+	if op.Remove != nil {
+		id, err := s.fieldValueId(i, name, op.Remove)
+		if err != nil {
+			return err
+		}
+		values = remove(values, id)
+	}
+	i.setValues(name, values)
+	return nil
+}
+
+func (s *Server) fieldValueId(i *issue, name string, v interface{}) (string, error) {
+	if name == "labels" {
+		label, ok := v.(string)
+		if !ok || label == "" || strings.ContainsAny(label, " \t") {
+			return "", fmt.Errorf("The label '%v' is invalid", v)
+		}
+		return label, nil
+	}
+	ref, _ := v.(map[string]interface{})
+	id, _ := ref["id"].(string)
+	refName, _ := ref["name"].(string)
+	if name == "fixVersions" {
+		for _, ver := range i.project.versions {
+			if ver.Id == id || ver.Name == refName {
+				return ver.Id, nil
+			}
+		}
+		return "", fmt.Errorf("Version name '%s' is not valid", refName+id)
+	}
+	for _, comp := range i.project.components {
+		if comp.Id == id || comp.Name == refName {
+			return comp.Id, nil
+		}
+	}
+	return "", fmt.Errorf("Component name '%s' is not valid", refName+id)
+}
+
+func (s *Server) toIssue(i *issue, fields []string) *internal.Issue {
+	all := map[string]interface{}{
+		"summary":     i.summary,
+		"description": i.description,
+		"issuetype":   map[string]interface{}{"name": i.issueType},
+		"project":     map[string]interface{}{"id": i.project.id, "key": i.project.key, "name": i.project.name},
+		"status": map[string]interface{}{
+			"name": i.status,
+			"statusCategory": map[string]interface{}{
+				"key":  statusCategories[i.status],
+				"name": statusCategoryNames[statusCategories[i.status]],
+			},
+		},
+		"labels":      append([]string{}, i.labels...),
+		"fixVersions": s.versionRefs(i),
+		"components":  s.componentRefs(i),
+		"resolution":  nil,
+		"issuelinks":  s.issueLinks(i),
+		"created":     i.created.Format(jiraTimeLayout),
+	}
+	if i.resolved() {
+		all["resolution"] = map[string]interface{}{"name": "Done"}
+		all["resolutiondate"] = i.resolutionDate.Format(jiraTimeLayout)
+	}
+	for name, value := range i.custom {
+		all[name] = value
+	}
+	if i.parent != "" {
+		all["parent"] = map[string]interface{}{"key": i.parent}
+	}
+	issue := &internal.Issue{Id: i.id, Key: i.key, Fields: make(map[string]interface{})}
+	for name, value := range all {
+		if len(fields) == 0 || contains(fields, name) || contains(fields, "*all") {
+			issue.Fields[name] = value
+		}
+	}
+	return issue
+}
+
+func (s *Server) versionRefs(i *issue) []map[string]interface{} {
+	refs := []map[string]interface{}{}
+	for _, ver := range i.project.versions {
+		if contains(i.fixVersions, ver.Id) {
+			refs = append(refs, map[string]interface{}{"id": ver.Id, "name": ver.Name, "released": ver.Released, "archived": ver.Archived})
+		}
+	}
+	return refs
+}
+
+func (s *Server) componentRefs(i *issue) []map[string]interface{} {
+	refs := []map[string]interface{}{}
+	for _, comp := range i.project.components {
+		if contains(i.components, comp.Id) {
+			refs = append(refs, map[string]interface{}{"id": comp.Id, "name": comp.Name})
+		}
+	}
+	return refs
+}
+
+func (s *Server) project(prjKeyOrId string) *project {
+	for _, prj := range s.projects {
+		if prj.key == prjKeyOrId || prj.id == prjKeyOrId {
+			return prj
+		}
+	}
+	return nil
+}
+
+func (s *Server) version(verId string) (*project, int) {
+	for _, prj := range s.projects {
+		for idx, ver := range prj.versions {
+			if ver.Id == verId {
+				return prj, idx
+			}
+		}
+	}
+	return nil, -1
+}
+
+func (s *Server) component(compId string) (*project, int) {
+	for _, prj := range s.projects {
+		for idx, comp := range prj.components {
+			if comp.Id == compId {
+				return prj, idx
+			}
+		}
+	}
+	return nil, -1
+}
+
+func (s *Server) issue(issueKeyOrId string) *issue {
+	for _, i := range s.issues {
+		if i.key == issueKeyOrId || i.id == issueKeyOrId {
+			return i
+		}
+	}
+	return nil
+}
+
+func (s *Server) issueCount(prj *project) int {
+	n := 0
+	for _, i := range s.issues {
+		if i.project == prj {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) newId() string {
+	s.nextId++
+	return strconv.Itoa(s.nextId)
+}
+
+func (p *project) toProject() *internal.Project {
+	return &internal.Project{
+		Id:          p.id,
+		Key:         p.key,
+		Description: p.description,
+		Versions:    append([]internal.Version{}, p.versions...),
+		Components:  append([]internal.Component{}, p.components...),
+	}
+}
+
+func (i *issue) resolved() bool {
+	return statusCategories[i.status] == "done"
+}
+
+func (i *issue) setCustom(name string, value interface{}) {
+	if i.custom == nil {
+		i.custom = make(map[string]interface{})
+	}
+	i.custom[name] = value
+}
+
+func (i *issue) values(name string) []string {
+	switch name {
+	case "labels":
+		return append([]string{}, i.labels...)
+	case "fixVersions":
+		return append([]string{}, i.fixVersions...)
+	default:
+		return append([]string{}, i.components...)
+	}
+}
+
+func (i *issue) setValues(name string, values []string) {
+	switch name {
+	case "labels":
+		sort.Strings(values)
+		i.labels = values
+	case "fixVersions":
+		i.fixVersions = values
+	default:
+		i.components = values
+	}
+}
+
+func findVersion(prj *project, name string) *internal.Version {
+	for _, ver := range prj.versions {
+		if strings.EqualFold(ver.Name, name) {
+			return &ver
+		}
+	}
+	return nil
+}
+
+func findComponent(prj *project, name string) *internal.Component {
+	for _, comp := range prj.components {
+		if strings.EqualFold(comp.Name, name) {
+			return &comp
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func readJSON(rw http.ResponseWriter, req *http.Request, v interface{}) bool {
+	err := json.NewDecoder(req.Body).Decode(v)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("Unexpected request body (%s)", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func writeError(rw http.ResponseWriter, status int, msg string) {
+	writeJSON(rw, status, map[string]interface{}{"errorMessages": []string{msg}, "errors": map[string]string{}})
+}
+
+func writeFieldError(rw http.ResponseWriter, status int, field, msg string) {
+	writeJSON(rw, status, map[string]interface{}{"errorMessages": []string{}, "errors": map[string]string{field: msg}})
+}
@@ -0,0 +1,327 @@
+package fakejira
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal"
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func createTestServer(t *testing.T) (*Server, *internal.JiraRestClient) {
+	t.Helper()
+	s := NewServer()
+	s.Username = "username"
+	s.Token = "apikey"
+	s.AddProject("PRJ", "Projekt")
+	if _, err := s.AddVersion("PRJ", internal.Version{Name: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddVersion("PRJ", internal.Version{Name: "1.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddComponent("PRJ", internal.Component{Name: "Backend"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []struct{ summary, status string }{
+		{"Login", StatusDone},
+		{"Logout", StatusInProgress},
+		{"Export", StatusToDo},
+	} {
+		if _, err := s.AddIssue("PRJ", "Story", i.summary, i.status, "1.0.0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	u, _ := url.Parse(ts.URL)
+	c, err := internal.CreateRestClient(url.UserPassword("username", "apikey"), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+func TestServerVersions(t *testing.T) {
+	s, c := createTestServer(t)
+	prj, err := c.GetProject("PRJ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prj.Versions) != 2 {
+		t.Fatalf("got: %d versions - want: 2", len(prj.Versions))
+	}
+	prjId, _ := strconv.Atoi(prj.Id)
+	if err := c.CreateVersion(internal.Version{Name: "1.0.0", ProjectId: prjId}); err == nil {
+		t.Errorf("got: no error - want: error for duplicate version")
+	}
+	unresolved, err := c.GetVersionUnresolvedIssueCount(prj.Versions[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unresolved.IssuesUnresolvedCount != 2 {
+		t.Errorf("got: %d unresolved issues - want: 2", unresolved.IssuesUnresolvedCount)
+	}
+	ver := prj.Versions[0]
+	ver.Released = true
+	if err := c.UpdateVersion(ver); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MoveVersion(prj.Versions[1].Id, "First"); err != nil {
+		t.Fatal(err)
+	}
+	prj, _ = s.Project("PRJ")
+	var names []string
+	for _, v := range prj.Versions {
+		names = append(names, v.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"1.1.0", "1.0.0"}) {
+		t.Errorf("got: %v - want: [1.1.0 1.0.0]", names)
+	}
+	if !prj.Versions[1].Released {
+		t.Errorf("got: version 1.0.0 not released - want: released")
+	}
+	if err := c.DeleteVersion(prj.Versions[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteVersion(prj.Versions[0]); err == nil {
+		t.Errorf("got: no error - want: error for deleted version")
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	_, c := createTestServer(t)
+	tests := []struct {
+		jql  string
+		keys []string
+		err  bool
+	}{
+		{"", []string{"PRJ-1", "PRJ-2", "PRJ-3"}, false},
+		{"project = PRJ AND fixVersion = \"1.0.0\" ORDER BY key", []string{"PRJ-1", "PRJ-2", "PRJ-3"}, false},
+		{"project = PRJ AND resolution = Unresolved", []string{"PRJ-2", "PRJ-3"}, false},
+		{"statusCategory != Done AND NOT summary ~ log", []string{"PRJ-3"}, false},
+		{"key in (PRJ-1, PRJ-3) OR status = \"In Progress\"", []string{"PRJ-1", "PRJ-2", "PRJ-3"}, false},
+		{"labels is EMPTY AND (status = Done OR status = \"To Do\")", []string{"PRJ-1", "PRJ-3"}, false},
+		{"project = OTHER", nil, false},
+		{"unknown = 1", nil, true},
+		{"project = (PRJ", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jql, func(t *testing.T) {
+			issues, err := internal.SearchIssues(tt.jql, []string{"summary"}, c)
+			if (err != nil) != tt.err {
+				t.Fatalf("got: %v - want error: %v", err, tt.err)
+			}
+			var keys []string
+			for _, i := range issues {
+				keys = append(keys, i.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("got: %v - want: %v", keys, tt.keys)
+			}
+		})
+	}
+}
+
+func TestServerEditIssue(t *testing.T) {
+	s, c := createTestServer(t)
+	for _, res := range internal.AssignFixVersion([]string{"PRJ-3"}, "1.1.0", true, c) {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+	update := internal.IssueUpdate{Update: map[string][]internal.FieldOperation{
+		"labels":     {{Add: "backend"}},
+		"components": {{Add: map[string]string{"name": "Backend"}}},
+	}}
+	if err := c.EditIssue("PRJ-3", update); err != nil {
+		t.Fatal(err)
+	}
+	i, _ := s.Issue("PRJ-3")
+	if got := i.Field("fixVersions"); got != "1.1.0" {
+		t.Errorf("got: fixVersions %q - want: 1.1.0", got)
+	}
+	if got := i.Field("labels"); got != "backend" {
+		t.Errorf("got: labels %q - want: backend", got)
+	}
+	if got := i.Field("components"); got != "Backend" {
+		t.Errorf("got: components %q - want: Backend", got)
+	}
+	update = internal.IssueUpdate{Update: map[string][]internal.FieldOperation{
+		"fixVersions": {{Add: map[string]string{"name": "9.9.9"}}},
+	}}
+	if err := c.EditIssue("PRJ-3", update); err == nil {
+		t.Errorf("got: no error - want: error for unknown version")
+	}
+	if _, err := c.GetIssue("PRJ-99", nil); err == nil {
+		t.Errorf("got: no error - want: error for unknown issue")
+	}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	s, _ := createTestServer(t)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	c, _ := internal.CreateRestClient(url.UserPassword("username", "wrong"), u)
+	if _, err := c.GetProject("PRJ"); err == nil {
+		t.Errorf("got: no error - want: error for wrong token")
+	}
+}
+
+func TestServerSprints(t *testing.T) {
+	s, c := createTestServer(t)
+	boardId, err := s.AddBoard("PRJ", "PRJ Board", "scrum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddBoard("PRJ", "PRJ Kanban", "kanban"); err != nil {
+		t.Fatal(err)
+	}
+	boards, err := internal.ScrumBoards("PRJ", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 1 || boards[0].Id != boardId || boards[0].Location.ProjectKey != "PRJ" {
+		t.Fatalf("got: %+v - want: scrum board %d of PRJ", boards, boardId)
+	}
+	start, end := "2021-03-01T09:00:00.000Z", "2021-03-15T09:00:00.000Z"
+	sprint, err := c.CreateSprint(internal.Sprint{Name: "Sprint 1", StartDate: &start, EndDate: &end, OriginBoardId: boardId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateSprint(internal.Sprint{Name: "Sprint 1", OriginBoardId: 99}); err == nil {
+		t.Errorf("got: no error - want: error for unknown board")
+	}
+	if err := c.MoveIssuesToSprint(sprint.Id, []string{"PRJ-1", "PRJ-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateSprint(internal.Sprint{Id: sprint.Id, State: internal.SprintActive}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateSprint(internal.Sprint{Id: sprint.Id, State: internal.SprintClosed}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateSprint(internal.Sprint{Id: sprint.Id, State: internal.SprintActive}); err == nil {
+		t.Errorf("got: no error - want: error for reopening closed sprint")
+	}
+	if err := c.MoveIssuesToSprint(sprint.Id, []string{"PRJ-3"}); err == nil {
+		t.Errorf("got: no error - want: error for closed sprint")
+	}
+	sprints, err := c.GetSprints(boardId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sprints) != 1 || sprints[0].State != internal.SprintClosed {
+		t.Errorf("got: %+v - want: closed Sprint 1", sprints)
+	}
+	_, keys, _ := s.Sprint(sprint.Id)
+	if !reflect.DeepEqual(keys, []string{"PRJ-1"}) {
+		t.Errorf("got: %v - want: only the done issue PRJ-1 kept in the closed sprint", keys)
+	}
+}
+
+func TestServerTransitions(t *testing.T) {
+	s, c := createTestServer(t)
+	results := internal.ApplyTransitions(internal.PlanTransitions([]string{"PRJ-2", "PRJ-3"}, "Ready for Release", nil, c), c)
+	if results[0].Err != nil {
+		t.Fatal(results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Errorf("got: no error - want: Ready for Release not reachable from To Do")
+	}
+	if i, _ := s.Issue("PRJ-2"); i.Field("status") != StatusReady {
+		t.Errorf("got: status %q - want: %s", i.Field("status"), StatusReady)
+	}
+	if err := c.TransitionIssue("PRJ-2", "41", nil); err == nil {
+		t.Errorf("got: no error - want: error for missing resolution")
+	}
+	results = internal.ApplyTransitions(internal.PlanTransitions([]string{"PRJ-1", "PRJ-2"}, "done", map[string]string{"resolution": "Won't Do"}, c), c)
+	if !results[0].Skipped || results[1].Err != nil {
+		t.Fatalf("got: %+v - want: PRJ-1 skipped, PRJ-2 transitioned", results)
+	}
+	if i, _ := s.Issue("PRJ-2"); i.Field("status") != StatusDone || i.Field("resolution") == "" {
+		t.Errorf("got: status %q resolution %q - want: resolved Done", i.Field("status"), i.Field("resolution"))
+	}
+}
+
+func TestServerCreateIssue(t *testing.T) {
+	s, c := createTestServer(t)
+	fields := func(issueType, parent string) internal.IssueUpdate {
+		f := map[string]interface{}{
+			"project":     map[string]string{"key": "PRJ"},
+			"issuetype":   map[string]string{"name": issueType},
+			"summary":     "Deploy",
+			"fixVersions": []map[string]string{{"name": "1.1.0"}},
+		}
+		if parent != "" {
+			f["parent"] = map[string]string{"key": parent}
+		}
+		return internal.IssueUpdate{Fields: f}
+	}
+	created, err := c.CreateIssue(fields("Task", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "PRJ-4" {
+		t.Errorf("got: %s - want: PRJ-4", created.Key)
+	}
+	if _, err := c.CreateIssue(fields("Sub-task", "PRJ-4")); err != nil {
+		t.Fatal(err)
+	}
+	if i, _ := s.Issue("PRJ-5"); i.Field("parent") != "PRJ-4" || i.Field("fixVersions") != "1.1.0" || i.Field("status") != StatusToDo {
+		t.Errorf("got: %+v - want: open sub-task of PRJ-4 in 1.1.0", i)
+	}
+	for _, f := range []internal.IssueUpdate{fields("Task", "PRJ-4"), fields("Sub-task", ""), fields("Sub-task", "PRJ-5"), fields("", "")} {
+		if _, err := c.CreateIssue(f); err == nil {
+			t.Errorf("got: no error - want: error for %v", f.Fields)
+		}
+	}
+}
+
+func TestServerComments(t *testing.T) {
+	s, c := createTestServer(t)
+	results := internal.CommentIssues([]string{"PRJ-1"}, "Ausgeliefert in {version}", internal.TemplateValues{Version: "1.0.0"},
+		&internal.Visibility{Type: internal.VisibilityGroup, Value: "jira-software-users"}, c)
+	if results[0].Err != nil {
+		t.Fatal(results[0].Err)
+	}
+	comments, _ := s.Comments("PRJ-1")
+	if len(comments) != 1 || adf.ToText(comments[0].Body) != "Ausgeliefert in 1.0.0" || comments[0].Visibility.Value != "jira-software-users" {
+		t.Errorf("got: %+v - want: comment for group jira-software-users", comments)
+	}
+	err := c.AddComment("PRJ-1", internal.Comment{Body: adf.FromMarkdown("x"), Visibility: &internal.Visibility{Type: internal.VisibilityRole, Value: "Kunden"}})
+	if err == nil {
+		t.Errorf("got: no error - want: error for unknown role")
+	}
+	if err := c.AddComment("PRJ-99", internal.Comment{Body: adf.FromMarkdown("x")}); err == nil {
+		t.Errorf("got: no error - want: error for unknown issue")
+	}
+}
+
+func TestServerVersionReport(t *testing.T) {
+	s, c := createTestServer(t)
+	created := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	for k, points := range map[string]float64{"PRJ-1": 5, "PRJ-2": 8, "PRJ-3": 2} {
+		if err := s.SetIssueField(k, "customfield_10016", points); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetIssueDates(k, created, created.AddDate(0, 0, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prj, _ := s.Project("PRJ")
+	p, err := internal.VersionReport(prj, "1.0.0", "customfield_10016", created.AddDate(0, 0, 2), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Done != 1 || p.InProgress != 1 || p.ToDo != 1 || p.Points != 15 || p.DonePoints != 5 {
+		t.Errorf("got: %+v - want: 5 of 15 points done", p)
+	}
+	if len(p.Burndown) != 3 || p.Burndown[0].Points != 15 || p.Burndown[2].Points != 10 {
+		t.Errorf("got: %+v - want: 15 points burned down to 10", p.Burndown)
+	}
+}
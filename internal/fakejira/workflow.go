@@ -0,0 +1,106 @@
+package fakejira
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type transition struct {
+	id         string
+	name       string
+	to         string
+	resolution bool
+}
+
+// workflow lists the transitions from each status, the transition to Done requires a resolution.
+var workflow = map[string][]transition{
+	StatusToDo:       {{id: "11", name: "Start Progress", to: StatusInProgress}},
+	StatusInProgress: {{id: "21", name: "Ready for Release", to: StatusReady}, {id: "31", name: "Stop Progress", to: StatusToDo}},
+	StatusReady:      {{id: "41", name: "Release", to: StatusDone, resolution: true}, {id: "21", name: "Reopen", to: StatusInProgress}},
+	StatusDone:       {{id: "51", name: "Reopen", to: StatusToDo}},
+}
+
+var resolutions = []map[string]interface{}{
+	{"id": "10000", "name": "Done"},
+	{"id": "10001", "name": "Won't Do"},
+}
+
+func (s *Server) getTransitions(rw http.ResponseWriter, req *http.Request, issueKey string) {
+	i := s.issue(issueKey)
+	if i == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	expand := req.URL.Query().Get("expand") == "transitions.fields"
+	var transitions []map[string]interface{}
+	for _, tr := range workflow[i.status] {
+		t := map[string]interface{}{
+			"id":   tr.id,
+			"name": tr.name,
+			"to": map[string]interface{}{
+				"name":           tr.to,
+				"statusCategory": map[string]interface{}{"key": statusCategories[tr.to]},
+			},
+		}
+		if expand {
+			fields := map[string]interface{}{}
+			if tr.resolution {
+				fields["resolution"] = map[string]interface{}{
+					"required":        true,
+					"name":            "Resolution",
+					"hasDefaultValue": false,
+					"allowedValues":   resolutions,
+				}
+			}
+			t["fields"] = fields
+		}
+		transitions = append(transitions, t)
+	}
+	writeJSON(rw, http.StatusOK, map[string]interface{}{"transitions": transitions})
+}
+
+func (s *Server) postTransition(rw http.ResponseWriter, req *http.Request, issueKey string) {
+	i := s.issue(issueKey)
+	if i == nil {
+		writeError(rw, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	body := struct {
+		Transition struct {
+			Id string `json:"id"`
+		} `json:"transition"`
+		Fields map[string]map[string]interface{} `json:"fields"`
+	}{}
+	if !readJSON(rw, req, &body) {
+		return
+	}
+	for _, tr := range workflow[i.status] {
+		if tr.id != body.Transition.Id {
+			continue
+		}
+		if tr.resolution {
+			res, ok := body.Fields["resolution"]
+			if !ok || !validResolution(res) {
+				writeFieldError(rw, http.StatusBadRequest, "resolution", "Resolution is required.")
+				return
+			}
+		}
+		i.status = tr.to
+		if i.resolved() {
+			i.resolutionDate = time.Now()
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeError(rw, http.StatusBadRequest, fmt.Sprintf("Transition id '%s' is not valid for this issue.", body.Transition.Id))
+}
+
+func validResolution(value map[string]interface{}) bool {
+	for _, r := range resolutions {
+		if value["id"] == r["id"] || value["name"] == r["name"] {
+			return true
+		}
+	}
+	return false
+}
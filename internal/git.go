@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var issueKeyRegexp = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+func ExtractIssueKeys(text string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, k := range issueKeyRegexp.FindAllString(text, -1) {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func IssueProjectKey(issueKey string) string {
+	i := strings.LastIndex(issueKey, "-")
+	if i < 0 {
+		return issueKey
+	}
+	return issueKey[:i]
+}
+
+func FilterIssueKeys(keys, prjKeys []string) []string {
+	prjs := make(map[string]bool)
+	for _, pk := range prjKeys {
+		prjs[pk] = true
+	}
+	var filtered []string
+	for _, k := range keys {
+		if prjs[IssueProjectKey(k)] {
+			filtered = append(filtered, k)
+		}
+	}
+	return filtered
+}
+
+func GitCommitMessages(dir, revRange string) ([]string, error) {
+	out, err := git(dir, "log", "--format=%B%x00", revRange)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []string
+	for _, m := range strings.Split(out, "\x00") {
+		m = strings.TrimSpace(m)
+		if m != "" {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+func GitIssueKeys(dir, revRange string) ([]string, error) {
+	msgs, err := GitCommitMessages(dir, revRange)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := GitBranches(dir, revRange)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractIssueKeys(strings.Join(append(msgs, branches...), "\n")), nil
+}
+
+func GitBranches(dir, revRange string) ([]string, error) {
+	args := []string{"for-each-ref", "--format=%(refname:short)"}
+	from, to := splitRevRange(revRange)
+	args = append(args, "--merged", to)
+	if from != "" {
+		args = append(args, "--no-merged", from)
+	}
+	out, err := git(dir, append(args, "refs/heads", "refs/remotes")...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+func splitRevRange(revRange string) (string, string) {
+	i := strings.Index(revRange, "..")
+	if i < 0 {
+		return "", revRange
+	}
+	to := strings.TrimPrefix(revRange[i+2:], ".")
+	if to == "" {
+		to = "HEAD"
+	}
+	return revRange[:i], to
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s fehlgeschlagen (%s)", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestExtractIssueKeys(t *testing.T) {
+	tests := []struct {
+		testcase string
+		text     string
+		keys     []string
+	}{
+		{"single key", "DB-12 fix login", []string{"DB-12"}},
+		{"several keys", "[DB-12] fix login, closes MN-3 and DB-12", []string{"DB-12", "MN-3"}},
+		{"branch name", "feature/AUTH_2-7-token-refresh", []string{"AUTH_2-7"}},
+		{"no key", "fix typo in README", nil},
+		{"lowercase and zero", "db-12 DB-0 xDB-4", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			keys := ExtractIssueKeys(tt.text)
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("got: %v - want: %v", keys, tt.keys)
+			}
+		})
+	}
+}
+
+func TestFilterIssueKeys(t *testing.T) {
+	keys := FilterIssueKeys([]string{"DB-1", "MN-2", "REL-3", "DB-4"}, []string{"DB", "REL"})
+	want := []string{"DB-1", "REL-3", "DB-4"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got: %v - want: %v", keys, want)
+	}
+}
+
+func TestSplitRevRange(t *testing.T) {
+	tests := []struct {
+		revRange string
+		from     string
+		to       string
+	}{
+		{"v1..v2", "v1", "v2"},
+		{"v1...v2", "v1", "v2"},
+		{"v1..", "v1", "HEAD"},
+		{"v2", "", "v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.revRange, func(t *testing.T) {
+			from, to := splitRevRange(tt.revRange)
+			if from != tt.from || to != tt.to {
+				t.Errorf("got: %s %s - want: %s %s", from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestGitCommitMessages(t *testing.T) {
+	dir := createTestRepository(t)
+	msgs, err := GitCommitMessages(dir, "v1..HEAD")
+	if err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	want := []string{"DB-3 second change", "DB-1 first change\n\nrefs MN-2"}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Errorf("got: %q - want: %q", msgs, want)
+	}
+	_, err = GitCommitMessages(dir, "v2..HEAD")
+	if err == nil {
+		t.Errorf("got: no error - want: error")
+	}
+}
+
+func TestGitIssueKeys(t *testing.T) {
+	dir := createTestRepository(t)
+	keys, err := GitIssueKeys(dir, "v1..master")
+	if err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	want := []string{"DB-3", "DB-1", "MN-2", "REL-4"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got: %v - want: %v", keys, want)
+	}
+}
+
+func createTestRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m"}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", "master"},
+		append(commit, "initial"),
+		{"branch", "feature/DB-9-old"},
+		{"tag", "v1"},
+		{"checkout", "-q", "-b", "feature/REL-4-calendar"},
+		append(commit, "DB-1 first change\n\nrefs MN-2"),
+		{"checkout", "-q", "master"},
+		{"merge", "-q", "--ff-only", "feature/REL-4-calendar"},
+		append(commit, "DB-3 second change"),
+	} {
+		if _, err := git(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const searchPageSize = 100
+
+var orderByRegexp = regexp.MustCompile(`(?i)\s*\border\s+by\b`)
+
+type Issue struct {
+	Id     string                 `json:"id"`
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+type SearchResult struct {
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+}
+
+type IssueUpdate struct {
+	Fields map[string]interface{}      `json:"fields,omitempty"`
+	Update map[string][]FieldOperation `json:"update,omitempty"`
+}
+
+type FieldOperation struct {
+	Add    interface{} `json:"add,omitempty"`
+	Remove interface{} `json:"remove,omitempty"`
+	Set    interface{} `json:"set,omitempty"`
+}
+
+type IssueResult struct {
+	Key string
+	Err error
+}
+
+func (i Issue) Field(name string) string {
+	if name == "key" {
+		return i.Key
+	}
+	return fieldString(i.Fields[name])
+}
+
+func fieldString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []interface{}:
+		var values []string
+		for _, e := range t {
+			values = append(values, fieldString(e))
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		if adf.IsDoc(t) {
+			doc, err := adf.Parse(t)
+			if err == nil {
+				return adf.ToText(doc)
+			}
+		}
+		for _, k := range []string{"name", "displayName", "value", "key"} {
+			if s, ok := t[k].(string); ok {
+				return s
+			}
+		}
+		return ""
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func SearchIssues(jql string, fields []string, c RestClient) ([]Issue, error) {
+	var issues []Issue
+	for startAt := 0; ; {
+		res, err := c.SearchIssues(jql, fields, startAt, searchPageSize)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, res.Issues...)
+		startAt += len(res.Issues)
+		if len(res.Issues) == 0 || startAt >= res.Total {
+			return issues, nil
+		}
+	}
+}
+
+func ValidateIssues(issueKeys []string, fields []string, c RestClient) ([]Issue, []string, error) {
+	var issues []Issue
+	var missing []string
+	for _, k := range issueKeys {
+		issue, err := c.GetIssue(k, fields)
+		t, ok := err.(RestError)
+		switch {
+		case ok && t.Status() == http.StatusNotFound:
+			missing = append(missing, k)
+		case err != nil:
+			return nil, nil, fmt.Errorf("Vorgang %s kann nicht gelesen werden (%s)", k, err)
+		default:
+			issues = append(issues, *issue)
+		}
+	}
+	return issues, missing, nil
+}
+
+func ProjectJQL(prjKeys []string, jql string) string {
+	var quoted []string
+	for _, k := range prjKeys {
+		quoted = append(quoted, strconv.Quote(k))
+	}
+	prjJQL := fmt.Sprintf("project in (%s)", strings.Join(quoted, ","))
+	var orderBy string
+	if i := orderByIndex(jql); i >= 0 {
+		jql, orderBy = jql[:i], " "+strings.TrimSpace(jql[i:])
+	}
+	if strings.TrimSpace(jql) == "" {
+		return prjJQL + orderBy
+	}
+	return fmt.Sprintf("%s AND (%s)%s", prjJQL, strings.TrimSpace(jql), orderBy)
+}
+
+// orderByIndex returns the start of the ORDER BY clause outside of quoted strings or -1.
+func orderByIndex(jql string) int {
+	quoted := make([]bool, len(jql))
+	var quote byte
+	for i := 0; i < len(jql); i++ {
+		c := jql[i]
+		if quote == 0 {
+			if c == '"' || c == '\'' {
+				quote = c
+				quoted[i] = true
+			}
+			continue
+		}
+		quoted[i] = true
+		switch {
+		case c == '\\' && i+1 < len(jql):
+			i++
+			quoted[i] = true
+		case c == quote:
+			quote = 0
+		}
+	}
+	for _, loc := range orderByRegexp.FindAllStringIndex(jql, -1) {
+		if !quoted[loc[1]-1] {
+			return loc[0]
+		}
+	}
+	return -1
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestIssue_Field(t *testing.T) {
+	issue := Issue{
+		Id:  "10001",
+		Key: "DB-1",
+		Fields: map[string]interface{}{
+			"summary":     "Deploy",
+			"status":      map[string]interface{}{"id": "3", "name": "In Progress"},
+			"assignee":    map[string]interface{}{"accountId": "5b10a", "displayName": "Max Mustermann"},
+			"fixVersions": []interface{}{map[string]interface{}{"name": "2021-07"}, map[string]interface{}{"name": "2021-08"}},
+			"labels":      []interface{}{"backend", "release"},
+			"storyPoints": 5.0,
+			"flagged":     true,
+			"resolution":  nil,
+			"description": map[string]interface{}{"type": "doc", "version": 1.0, "content": []interface{}{
+				map[string]interface{}{"type": "paragraph", "content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "Siehe "},
+					map[string]interface{}{"type": "text", "text": "Runbook", "marks": []interface{}{
+						map[string]interface{}{"type": "link", "attrs": map[string]interface{}{"href": "https://example.com"}},
+					}},
+				}},
+			}},
+		},
+	}
+	tests := []struct {
+		testcase string
+		field    string
+		value    string
+	}{
+		{"key", "key", "DB-1"},
+		{"string field", "summary", "Deploy"},
+		{"named field", "status", "In Progress"},
+		{"user field", "assignee", "Max Mustermann"},
+		{"list of named fields", "fixVersions", "2021-07,2021-08"},
+		{"list of strings", "labels", "backend,release"},
+		{"number field", "storyPoints", "5"},
+		{"bool field", "flagged", "true"},
+		{"empty field", "resolution", ""},
+		{"document field", "description", "Siehe Runbook (https://example.com)"},
+		{"missing field", "duedate", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			value := issue.Field(tt.field)
+			if value != tt.value {
+				t.Errorf("got: %s - want: %s", value, tt.value)
+			}
+		})
+	}
+}
+
+func TestSearchIssues(t *testing.T) {
+	tests := []struct {
+		testcase string
+		total    int
+		pageSize int
+		issues   int
+		calls    []int
+	}{
+		{"no issues", 0, 2, 0, []int{0}},
+		{"one page", 2, 2, 2, []int{0}},
+		{"several pages", 5, 2, 5, []int{0, 2, 4}},
+		{"total larger than result", 7, 0, 0, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &searchRestClient{total: tt.total, pageSize: tt.pageSize}
+			issues, err := SearchIssues("project = DB", []string{"summary"}, c)
+			if err != nil {
+				t.Errorf("got: %v - want: no error", err)
+			}
+			if len(issues) != tt.issues {
+				t.Errorf("got: %d - want: %d", len(issues), tt.issues)
+			}
+			if !reflect.DeepEqual(c.calls, tt.calls) {
+				t.Errorf("got: %v - want: %v", c.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestValidateIssues(t *testing.T) {
+	c := &TestRestClient{issues: []Issue{{Key: "DB-1"}, {Key: "MN-2"}}}
+	issues, missing, err := ValidateIssues([]string{"DB-1", "DB-7", "MN-2"}, []string{"summary"}, c)
+	if err != nil {
+		t.Errorf("got: %v - want: no error", err)
+	}
+	if !reflect.DeepEqual(issues, []Issue{{Key: "DB-1"}, {Key: "MN-2"}}) {
+		t.Errorf("got: %v - want: %v", issues, []Issue{{Key: "DB-1"}, {Key: "MN-2"}})
+	}
+	if !reflect.DeepEqual(missing, []string{"DB-7"}) {
+		t.Errorf("got: %v - want: %v", missing, []string{"DB-7"})
+	}
+}
+
+func TestProjectJQL(t *testing.T) {
+	tests := []struct {
+		testcase string
+		projects []string
+		jql      string
+		result   string
+	}{
+		{
+			"one project",
+			[]string{"DB"},
+			"fixVersion = \"2021-07\"",
+			"project in (\"DB\") AND (fixVersion = \"2021-07\")",
+		},
+		{
+			"some projects with order",
+			[]string{"DB", "MN"},
+			"statusCategory != Done order by key",
+			"project in (\"DB\",\"MN\") AND (statusCategory != Done) order by key",
+		},
+		{
+			"order only",
+			[]string{"DB"},
+			"ORDER BY created DESC",
+			"project in (\"DB\") ORDER BY created DESC",
+		},
+		{
+			"empty query",
+			[]string{"DB"},
+			"",
+			"project in (\"DB\")",
+		},
+		{
+			"order by in quoted text",
+			[]string{"DB"},
+			`summary ~ "order by" OR description ~ 'sort \' order by' ORDER BY key`,
+			`project in ("DB") AND (summary ~ "order by" OR description ~ 'sort \' order by') ORDER BY key`,
+		},
+		{
+			"order by only in quoted text",
+			[]string{"DB"},
+			`summary ~ "order by"`,
+			`project in ("DB") AND (summary ~ "order by")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			result := ProjectJQL(tt.projects, tt.jql)
+			if result != tt.result {
+				t.Errorf("got: %s - want: %s", result, tt.result)
+			}
+		})
+	}
+}
+
+type searchRestClient struct {
+	TestRestClient
+	total    int
+	pageSize int
+	calls    []int
+}
+
+func (c *searchRestClient) SearchIssues(jql string, fields []string, startAt, maxResults int) (*SearchResult, error) {
+	c.calls = append(c.calls, startAt)
+	res := &SearchResult{StartAt: startAt, MaxResults: maxResults, Total: c.total}
+	for i := startAt; i < c.total && i < startAt+c.pageSize; i++ {
+		res.Issues = append(res.Issues, Issue{Key: "DB-" + strconv.Itoa(i+1)})
+	}
+	return res, nil
+}
@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+type JournalEntry struct {
+	RunId     string    `json:"runId"`
+	Time      time.Time `json:"time"`
+	Operation Operation `json:"operation"`
+	ProjectId int       `json:"projectId"`
+	// ProjectKey is missing for entries journaled before the key was recorded
+	ProjectKey string   `json:"projectKey,omitempty"`
+	Before     *Version `json:"before,omitempty"`
+	After      *Version `json:"after,omitempty"`
+}
+
+func (e JournalEntry) Version() *Version {
+	if e.After != nil {
+		return e.After
+	}
+	return e.Before
+}
+
+// Project returns the project key, the project id if the key is not known.
+func (e JournalEntry) Project() string {
+	if e.ProjectKey != "" {
+		return e.ProjectKey
+	}
+	return strconv.Itoa(e.ProjectId)
+}
+
+func (e JournalEntry) UndoChange() Change {
+	ch := Change{
+		Project:   e.Project(),
+		Object:    fmt.Sprintf("Version %s", e.Version().Name),
+		Operation: OpUpdate,
+		State:     fmt.Sprintf("Stand vor %s %s", e.Operation, e.Time.Format(time.RFC3339)),
+	}
+	switch e.Operation {
+	case OpCreate:
+		ch.Operation = OpDelete
+	case OpDelete:
+		ch.Operation = OpCreate
+	}
+	return ch
+}
+
+type Journal struct {
+	Path string
+}
+
+func (j *Journal) Append(e JournalEntry) error {
+	f, err := os.OpenFile(j.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	return json.NewEncoder(f).Encode(e)
+}
+
+// Entries returns the entries of the run, all entries without run id. A missing journal
+// has no entries.
+func (j *Journal) Entries(runId string) ([]JournalEntry, error) {
+	f, err := os.Open(j.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Journal %s kann nicht gelesen werden (%s)", j.Path, err)
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	var entries []JournalEntry
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		e := JournalEntry{}
+		err := json.Unmarshal(s.Bytes(), &e)
+		if err != nil {
+			return nil, fmt.Errorf("Journal %s ist ungültig (%s)", j.Path, err)
+		}
+		if runId == "" || e.RunId == runId {
+			entries = append(entries, e)
+		}
+	}
+	return entries, s.Err()
+}
+
+func NewRunId() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(b))
+}
+
+// JournalRestClient writes every change of a version to the journal. Journal failures after
+// a successful change are passed to Warn, the change itself is not reported as failed.
+type JournalRestClient struct {
+	RestClient
+	Journal *Journal
+	RunId   string
+	Entries int
+	Warn    func(err error)
+}
+
+func (c *JournalRestClient) CreateVersion(version Version) error {
+	err := c.RestClient.CreateVersion(version)
+	if err != nil {
+		return err
+	}
+	prj, after, _ := c.projectVersion(version.ProjectId, func(v Version) bool { return v.Name == version.Name })
+	if after == nil {
+		after = &version
+	}
+	c.append(OpCreate, version.ProjectId, prj, nil, after)
+	return nil
+}
+
+func (c *JournalRestClient) UpdateVersion(version Version) error {
+	byId := func(v Version) bool { return v.Id == version.Id }
+	prj, before, err := c.projectVersion(version.ProjectId, byId)
+	if err != nil {
+		return err
+	}
+	err = c.RestClient.UpdateVersion(version)
+	if err != nil {
+		return err
+	}
+	_, after, _ := c.projectVersion(version.ProjectId, byId)
+	if after == nil {
+		after = &version
+	}
+	c.append(versionOperation(before, after), version.ProjectId, prj, before, after)
+	return nil
+}
+
+func (c *JournalRestClient) DeleteVersion(version Version) error {
+	prj, before, err := c.projectVersion(version.ProjectId, func(v Version) bool { return v.Id == version.Id })
+	if err != nil {
+		return err
+	}
+	err = c.RestClient.DeleteVersion(version)
+	if err != nil {
+		return err
+	}
+	c.append(OpDelete, version.ProjectId, prj, before, nil)
+	return nil
+}
+
+func (c *JournalRestClient) projectVersion(prjId int, match func(Version) bool) (*Project, *Version, error) {
+	prj, err := c.RestClient.GetProject(strconv.Itoa(prjId))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Projekt %d kann nicht gelesen werden (%s)", prjId, err)
+	}
+	for _, v := range prj.Versions {
+		if match(v) {
+			return prj, &v, nil
+		}
+	}
+	return prj, nil, nil
+}
+
+func (c *JournalRestClient) append(op Operation, prjId int, prj *Project, before, after *Version) {
+	e := JournalEntry{
+		RunId:     c.RunId,
+		Time:      time.Now(),
+		Operation: op,
+		ProjectId: prjId,
+		Before:    before,
+		After:     after,
+	}
+	if prj != nil {
+		e.ProjectKey = prj.Key
+	}
+	err := c.Journal.Append(e)
+	if err != nil {
+		if c.Warn != nil {
+			c.Warn(fmt.Errorf("Journal %s kann %s nicht aufnehmen (%s)", c.Journal.Path, op, err))
+		}
+		return
+	}
+	c.Entries++
+}
+
+func versionOperation(before, after *Version) Operation {
+	switch {
+	case before == nil:
+		return OpUpdate
+	case after.Archived && !before.Archived:
+		return OpArchive
+	case after.Released && !before.Released:
+		return OpRelease
+	default:
+		return OpUpdate
+	}
+}
+
+func Undo(entries []JournalEntry, c RestClient) []error {
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		var err error
+		switch {
+		case e.Operation == OpCreate && e.After != nil:
+			err = deleteCreatedVersion(*e.After, e.ProjectId, c)
+		case e.Operation == OpDelete && e.Before != nil:
+			ver := *e.Before
+			ver.Id = ""
+			ver.UserReleaseDate = nil
+			err = c.CreateVersion(ver)
+		case e.Before != nil:
+			ver := *e.Before
+			ver.UserReleaseDate = nil
+			err = c.UpdateVersion(ver)
+		default:
+			err = fmt.Errorf("Änderung %s vom %s kann nicht rückgängig gemacht werden", e.Operation, e.Time.Format(time.RFC3339))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// deleteCreatedVersion looks up the id by name for versions journaled as submitted.
+func deleteCreatedVersion(ver Version, prjId int, c RestClient) error {
+	if ver.Id == "" {
+		prj, err := c.GetProject(strconv.Itoa(prjId))
+		if err != nil {
+			return fmt.Errorf("Projekt %d kann nicht gelesen werden (%s)", prjId, err)
+		}
+		v, err := getVersion(prj, ver.Name)
+		if err != nil {
+			return err
+		}
+		ver = *v
+	}
+	return c.DeleteVersion(ver)
+}
@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestJournalRestClient(t *testing.T) {
+	relDate := "2021-07-06"
+	journal := &Journal{Path: filepath.Join(t.TempDir(), "journal.jsonl")}
+	rc := &journalTestRestClient{}
+	rc.versions = []Version{{Id: "10001", Name: "2021-07", ProjectId: 10000}}
+	c := &JournalRestClient{RestClient: rc, Journal: journal, RunId: "run-1"}
+
+	prj, _ := c.GetProject("DB")
+	if err := CreateVersion(prj, "2021-08", c); err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	if err := ReleaseVersion(prj, "2021-07", relDate, c); err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	c.RunId = "run-2"
+	prj, _ = c.GetProject("DB")
+	if err := ArchiveVersion(prj, "2021-08", c); err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+
+	entries, err := journal.Entries("run-1")
+	if err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	var ops []Operation
+	for _, e := range entries {
+		ops = append(ops, e.Operation)
+	}
+	if !reflect.DeepEqual(ops, []Operation{OpCreate, OpRelease}) {
+		t.Errorf("got: %v - want: %v", ops, []Operation{OpCreate, OpRelease})
+	}
+	if entries[0].Before != nil || entries[0].After.Id != "10002" || entries[0].Project() != "DB" {
+		t.Errorf("got: %v %v - want: created version 10002", entries[0].Before, entries[0].After)
+	}
+	if entries[1].Before.Released || !entries[1].After.Released {
+		t.Errorf("got: %v %v - want: released version", entries[1].Before, entries[1].After)
+	}
+	if c.Entries != 3 {
+		t.Errorf("got: %d - want: 3", c.Entries)
+	}
+	all, _ := journal.Entries("")
+	if len(all) != 3 {
+		t.Errorf("got: %d - want: 3", len(all))
+	}
+	if missing, err := (&Journal{Path: filepath.Join(t.TempDir(), "missing.jsonl")}).Entries(""); err != nil || len(missing) != 0 {
+		t.Errorf("got: %v %v - want: no entries of a missing journal", missing, err)
+	}
+
+	errs := Undo(entries, c)
+	if len(errs) != 0 {
+		t.Errorf("got: %v - want: no errors", errs)
+	}
+	want := []Version{{Id: "10001", Name: "2021-07", ProjectId: 10000}}
+	if !reflect.DeepEqual(rc.versions, want) {
+		t.Errorf("got: %v - want: %v", rc.versions, want)
+	}
+}
+
+func TestUndo_Delete(t *testing.T) {
+	rc := &journalTestRestClient{}
+	entries := []JournalEntry{
+		{RunId: "run-1", Operation: OpDelete, ProjectId: 10000, Before: &Version{Id: "10001", Name: "2021-07", ProjectId: 10000}},
+		{RunId: "run-1", Operation: OpCreate, ProjectId: 10000},
+	}
+	errs := Undo(entries, rc)
+	if len(errs) != 1 {
+		t.Errorf("got: %v - want: one error", errs)
+	}
+	if len(rc.versions) != 1 || rc.versions[0].Name != "2021-07" {
+		t.Errorf("got: %v - want: recreated version 2021-07", rc.versions)
+	}
+}
+
+func TestJournalEntry_UndoChange(t *testing.T) {
+	tests := []struct {
+		entry     JournalEntry
+		operation Operation
+	}{
+		{JournalEntry{Operation: OpCreate, ProjectKey: "DB", After: &Version{Name: "2021-07"}}, OpDelete},
+		{JournalEntry{Operation: OpDelete, Before: &Version{Name: "2021-07"}}, OpCreate},
+		{JournalEntry{Operation: OpRelease, Before: &Version{Name: "2021-07"}, After: &Version{Name: "2021-07", Released: true}}, OpUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.entry.Operation), func(t *testing.T) {
+			ch := tt.entry.UndoChange()
+			if ch.Operation != tt.operation || ch.Object != "Version 2021-07" || (tt.entry.ProjectKey != "" && ch.Project != "DB") {
+				t.Errorf("got: %v - want: %s", ch, tt.operation)
+			}
+		})
+	}
+}
+
+type journalTestRestClient struct {
+	TestRestClient
+}
+
+func (c *journalTestRestClient) CreateVersion(version Version) error {
+	version.Id = strconv.Itoa(10001 + len(c.versions))
+	c.versions = append(c.versions, version)
+	return nil
+}
+
+func (c *journalTestRestClient) UpdateVersion(version Version) error {
+	for i, v := range c.versions {
+		if v.Id == version.Id {
+			c.versions[i] = version
+		}
+	}
+	return nil
+}
+
+func (c *journalTestRestClient) GetProject(string) (*Project, error) {
+	return &Project{Id: "10000", Key: "DB", Versions: c.versions}, nil
+}
+
+type failingProjectRestClient struct {
+	journalTestRestClient
+	failGetProject bool
+}
+
+func (c *failingProjectRestClient) GetProject(prjKey string) (*Project, error) {
+	if c.failGetProject {
+		return nil, RestError{"503 Service Unavailable", 503}
+	}
+	return c.journalTestRestClient.GetProject(prjKey)
+}
+
+func TestJournalRestClient_Failures(t *testing.T) {
+	journal := &Journal{Path: filepath.Join(t.TempDir(), "journal.jsonl")}
+	rc := &failingProjectRestClient{failGetProject: true}
+	c := &JournalRestClient{RestClient: rc, Journal: journal, RunId: "run-1"}
+	if err := c.CreateVersion(Version{Name: "2021-08", ProjectId: 10000}); err != nil {
+		t.Fatalf("got: %v - want: no error", err)
+	}
+	entries, _ := journal.Entries("run-1")
+	if len(entries) != 1 || entries[0].After == nil || entries[0].After.Name != "2021-08" {
+		t.Fatalf("got: %v - want: submitted version journaled", entries)
+	}
+	rc.failGetProject = false
+	if errs := Undo(entries, c); len(errs) != 0 || len(rc.versions) != 0 {
+		t.Errorf("got: %v %v - want: created version deleted by name", errs, rc.versions)
+	}
+
+	var warnings []error
+	c = &JournalRestClient{RestClient: rc, Journal: &Journal{Path: t.TempDir()}, RunId: "run-2", Warn: func(err error) { warnings = append(warnings, err) }}
+	if err := c.CreateVersion(Version{Name: "2021-09", ProjectId: 10000}); err != nil {
+		t.Errorf("got: %v - want: created version not reported as failed", err)
+	}
+	if len(warnings) != 1 || c.Entries != 0 {
+		t.Errorf("got: %v - want: one journal warning", warnings)
+	}
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type LabelEdit struct {
+	Add    []string
+	Remove []string
+	// Rename maps old to new labels, only issues with the old label get the new one
+	Rename map[string]string
+}
+
+type LabelResult struct {
+	Key    string
+	Before []string
+	After  []string
+	Err    error
+	ops    []FieldOperation
+}
+
+func (r LabelResult) Change() Change {
+	return Change{
+		Project:   IssueProjectKey(r.Key),
+		Object:    "Vorgang " + r.Key,
+		Operation: OpEditLabels,
+		State:     fmt.Sprintf("%s → %s", strings.Join(r.Before, ","), strings.Join(r.After, ",")),
+	}
+}
+
+// ParseLabelEdit reads comma separated labels to add and remove and "old=new" pairs to rename.
+func ParseLabelEdit(add, remove, rename string) (LabelEdit, error) {
+	edit := LabelEdit{Add: splitLabels(add), Remove: splitLabels(remove), Rename: make(map[string]string)}
+	for _, pair := range splitLabels(rename) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" || strings.TrimSpace(kv[1]) == "" {
+			return edit, fmt.Errorf("Umbenennung %s ist ungültig (alt=neu)", pair)
+		}
+		edit.Rename[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	var labels []string
+	labels = append(labels, edit.Add...)
+	labels = append(labels, edit.Remove...)
+	for old, to := range edit.Rename {
+		labels = append(labels, old, to)
+	}
+	for _, l := range labels {
+		if strings.ContainsAny(l, " \t") {
+			return edit, fmt.Errorf("Label %s ist ungültig (keine Leerzeichen)", l)
+		}
+	}
+	if len(edit.Add)+len(edit.Remove)+len(edit.Rename) == 0 {
+		return edit, fmt.Errorf("Keine Labels angegeben")
+	}
+	return edit, nil
+}
+
+func splitLabels(list string) []string {
+	var labels []string
+	for _, l := range strings.Split(list, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// PlanLabels reads the labels of the issues and returns the issues to change with their
+// labels before and after the edit, and the issues that cannot be read.
+func PlanLabels(issueKeys []string, edit LabelEdit, c RestClient) []LabelResult {
+	var plans []LabelResult
+	for _, k := range issueKeys {
+		issue, err := c.GetIssue(k, []string{"labels"})
+		if err != nil {
+			plans = append(plans, LabelResult{Key: k, Err: err})
+			continue
+		}
+		before := issueLabels(issue)
+		ops, after := labelOperations(before, edit)
+		if len(ops) == 0 {
+			continue
+		}
+		plans = append(plans, LabelResult{Key: k, Before: before, After: after, ops: ops})
+	}
+	return plans
+}
+
+// ApplyLabels adds, removes and renames the labels of the planned issues by update
+// operations. Only the changed issues and the failures are returned.
+func ApplyLabels(plans []LabelResult, c RestClient) []LabelResult {
+	var results []LabelResult
+	for _, r := range plans {
+		if r.Err == nil {
+			r.Err = c.EditIssue(r.Key, IssueUpdate{Update: map[string][]FieldOperation{"labels": r.ops}})
+		}
+		r.ops = nil
+		results = append(results, r)
+	}
+	return results
+}
+
+func labelOperations(labels []string, edit LabelEdit) ([]FieldOperation, []string) {
+	has := make(map[string]bool)
+	for _, l := range labels {
+		has[l] = true
+	}
+	var ops []FieldOperation
+	add := func(l string) {
+		if !has[l] {
+			ops = append(ops, FieldOperation{Add: l})
+			has[l] = true
+		}
+	}
+	remove := func(l string) {
+		if has[l] {
+			ops = append(ops, FieldOperation{Remove: l})
+			delete(has, l)
+		}
+	}
+	var renamed []string
+	for old := range edit.Rename {
+		renamed = append(renamed, old)
+	}
+	sort.Strings(renamed)
+	for _, old := range renamed {
+		if has[old] {
+			remove(old)
+			add(edit.Rename[old])
+		}
+	}
+	for _, l := range edit.Remove {
+		remove(l)
+	}
+	for _, l := range edit.Add {
+		add(l)
+	}
+	var after []string
+	for l := range has {
+		after = append(after, l)
+	}
+	sort.Strings(after)
+	return ops, after
+}
+
+func issueLabels(issue *Issue) []string {
+	var labels []string
+	list, _ := issue.Fields["labels"].([]interface{})
+	for _, l := range list {
+		if s, ok := l.(string); ok {
+			labels = append(labels, s)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func LabelTable(results []LabelResult) ([]string, [][]string) {
+	var rows [][]string
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		rows = append(rows, []string{r.Key, strings.Join(r.Before, ","), strings.Join(r.After, ",")})
+	}
+	return []string{"vorgang", "vorher", "nachher"}, rows
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabelEdit(t *testing.T) {
+	edit, err := ParseLabelEdit("released-2021-07, backend", "wip", "relase=release, fronted = frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LabelEdit{
+		Add:    []string{"released-2021-07", "backend"},
+		Remove: []string{"wip"},
+		Rename: map[string]string{"relase": "release", "fronted": "frontend"},
+	}
+	if !reflect.DeepEqual(edit, want) {
+		t.Errorf("got: %+v - want: %+v", edit, want)
+	}
+	for _, args := range [][3]string{{"", "", ""}, {"two words", "", ""}, {"", "", "relase"}, {"", "", "=release"}} {
+		if _, err := ParseLabelEdit(args[0], args[1], args[2]); err == nil {
+			t.Errorf("got: no error - want: error for %q", args)
+		}
+	}
+}
+
+func TestPlanLabels(t *testing.T) {
+	labels := func(l ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"labels": l}
+	}
+	c := &TestRestClient{issues: []Issue{
+		{Key: "PRJ-1", Fields: labels("relase", "wip")},
+		{Key: "PRJ-2", Fields: labels("release", "released-2021-07")},
+		{Key: "PRJ-3", Fields: labels()},
+	}}
+	edit := LabelEdit{Add: []string{"released-2021-07"}, Remove: []string{"wip"}, Rename: map[string]string{"relase": "release"}}
+	if plans := PlanLabels([]string{"PRJ-1", "PRJ-2"}, edit, c); len(plans) != 1 || c.edited != nil {
+		t.Errorf("got: %+v %v - want: PRJ-1 planned, nothing edited", plans, c.edited)
+	}
+	results := ApplyLabels(PlanLabels([]string{"PRJ-1", "PRJ-2", "PRJ-3", "PRJ-4"}, edit, c), c)
+	want := []LabelResult{
+		{Key: "PRJ-1", Before: []string{"relase", "wip"}, After: []string{"release", "released-2021-07"}},
+		{Key: "PRJ-3", After: []string{"released-2021-07"}},
+	}
+	if len(results) != 3 || results[2].Key != "PRJ-4" || results[2].Err == nil {
+		t.Fatalf("got: %+v - want: PRJ-1, PRJ-3 changed and PRJ-4 failed", results)
+	}
+	if !reflect.DeepEqual(results[:2], want) {
+		t.Errorf("got: %+v - want: %+v", results[:2], want)
+	}
+	wantOps := []FieldOperation{{Remove: "relase"}, {Add: "release"}, {Remove: "wip"}, {Add: "released-2021-07"}}
+	if ops := c.edited["PRJ-1"].Update["labels"]; !reflect.DeepEqual(ops, wantOps) {
+		t.Errorf("got: %+v - want: %+v", ops, wantOps)
+	}
+	if _, ok := c.edited["PRJ-2"]; ok {
+		t.Errorf("got: PRJ-2 edited - want: unchanged issue skipped")
+	}
+	want0 := Change{Project: "PRJ", Object: "Vorgang PRJ-1", Operation: OpEditLabels, State: "relase,wip → release,released-2021-07"}
+	if ch := results[0].Change(); ch != want0 || !Destructive([]Change{ch}) {
+		t.Errorf("got: %v - want: %v", ch, want0)
+	}
+	header, rows := LabelTable(results)
+	if len(header) != 3 || !reflect.DeepEqual(rows, [][]string{{"PRJ-1", "relase,wip", "release,released-2021-07"}, {"PRJ-3", "", "released-2021-07"}}) {
+		t.Errorf("got: %v - want: rows of PRJ-1 and PRJ-3", rows)
+	}
+}
@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+type IssueLinkType struct {
+	Id      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward,omitempty"`
+	Outward string `json:"outward,omitempty"`
+}
+
+// IssueLink is read from the issuelinks field of an issue, where it has either an inward
+// or an outward issue, and created with both. The inward issue of a created link is the
+// subject of the outward description, e.g. inward issue "blocks" outward issue.
+type IssueLink struct {
+	Id           string        `json:"id,omitempty"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+type LinkedIssue struct {
+	Id     string                 `json:"id,omitempty"`
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+type LinkResult struct {
+	Key string
+	// Skipped is set for issues that are already linked
+	Skipped bool
+	Err     error
+	link    IssueLink
+	label   string
+}
+
+func (r LinkResult) Change() Change {
+	return Change{
+		Project:   IssueProjectKey(r.link.InwardIssue.Key),
+		Object:    "Vorgang " + r.link.InwardIssue.Key,
+		Operation: OpLink,
+		State:     fmt.Sprintf("%s %s", r.label, r.link.OutwardIssue.Key),
+	}
+}
+
+// LinkEdge reads From Label To, e.g. DEMO-3 blocks REL-1.
+type LinkEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// FindLinkType returns the link type by name, inward or outward description. outward
+// reports whether the description applies from the linking issue to the linked issues.
+func FindLinkType(types []IssueLinkType, phrase string) (IssueLinkType, bool, error) {
+	var names []string
+	for _, lt := range types {
+		switch {
+		case strings.EqualFold(lt.Outward, phrase), strings.EqualFold(lt.Name, phrase):
+			return lt, true, nil
+		case strings.EqualFold(lt.Inward, phrase):
+			return lt, false, nil
+		}
+		names = append(names, fmt.Sprintf("%s/%s", lt.Outward, lt.Inward))
+	}
+	return IssueLinkType{}, false, fmt.Errorf("Verknüpfung %s ist nicht vorhanden (möglich: %s)", phrase, strings.Join(names, ", "))
+}
+
+// PlanLinks returns the links to create from the issue to each of the issues, e.g. REL-1
+// "is blocked by" DEMO-3. Issues already linked by the same type and direction are skipped.
+func PlanLinks(issueKey string, issueKeys []string, phrase string, c RestClient) ([]LinkResult, error) {
+	types, err := c.GetIssueLinkTypes()
+	if err != nil {
+		return nil, err
+	}
+	lt, outward, err := FindLinkType(types, phrase)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := c.GetIssue(issueKey, []string{"issuelinks"})
+	if err != nil {
+		return nil, fmt.Errorf("Vorgang %s kann nicht gelesen werden (%s)", issueKey, err)
+	}
+	linked := make(map[string]bool)
+	for _, e := range issueEdges(*issue) {
+		if e.Label == lt.Outward {
+			linked[e.From+" "+e.To] = true
+		}
+	}
+	var plans []LinkResult
+	for _, k := range issueKeys {
+		from, to := issueKey, k
+		if !outward {
+			from, to = k, issueKey
+		}
+		if k == issueKey || linked[from+" "+to] {
+			plans = append(plans, LinkResult{Key: k, Skipped: true})
+			continue
+		}
+		plans = append(plans, LinkResult{Key: k, label: lt.Outward, link: IssueLink{
+			Type:         IssueLinkType{Name: lt.Name},
+			InwardIssue:  &LinkedIssue{Key: from},
+			OutwardIssue: &LinkedIssue{Key: to},
+		}})
+	}
+	return plans, nil
+}
+
+// ApplyLinks creates the planned links.
+func ApplyLinks(plans []LinkResult, c RestClient) []LinkResult {
+	var results []LinkResult
+	for _, r := range plans {
+		if !r.Skipped {
+			r.Err = c.CreateIssueLink(r.link)
+		}
+		results = append(results, LinkResult{Key: r.Key, Skipped: r.Skipped, Err: r.Err})
+	}
+	return results
+}
+
+// IssueLinks reads the issuelinks field of an issue.
+func IssueLinks(issue Issue) []IssueLink {
+	var links []IssueLink
+	data, err := json.Marshal(issue.Fields["issuelinks"])
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil
+	}
+	return links
+}
+
+func issueEdges(issue Issue) []LinkEdge {
+	var edges []LinkEdge
+	for _, l := range IssueLinks(issue) {
+		switch {
+		case l.OutwardIssue != nil:
+			edges = append(edges, LinkEdge{From: issue.Key, To: l.OutwardIssue.Key, Label: l.Type.Outward})
+		case l.InwardIssue != nil:
+			edges = append(edges, LinkEdge{From: l.InwardIssue.Key, To: issue.Key, Label: l.Type.Outward})
+		}
+	}
+	return edges
+}
+
+// LinkGraph returns the links of the issues and the summaries of the issues and their
+// linked issues by key.
+func LinkGraph(issues []Issue) ([]LinkEdge, map[string]string) {
+	var edges []LinkEdge
+	seen := make(map[LinkEdge]bool)
+	summaries := make(map[string]string)
+	for _, i := range issues {
+		summaries[i.Key] = i.Field("summary")
+		for _, l := range IssueLinks(i) {
+			for _, li := range []*LinkedIssue{l.InwardIssue, l.OutwardIssue} {
+				if li != nil && summaries[li.Key] == "" {
+					summaries[li.Key] = fieldString(li.Fields["summary"])
+				}
+			}
+		}
+		for _, e := range issueEdges(i) {
+			if !seen[e] {
+				seen[e] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges, summaries
+}
+
+// WriteGraph renders the links as Graphviz DOT or Mermaid flowchart.
+func WriteGraph(format string, edges []LinkEdge, summaries map[string]string) (string, error) {
+	var keys []string
+	for k := range summaries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	switch format {
+	case GraphDOT:
+		sb.WriteString("digraph links {\n  rankdir=LR;\n  node [shape=box];\n")
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "  %s [label=%s];\n", dotQuote(k), dotQuote(nodeLabel(k, summaries[k], "\n")))
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+		}
+		sb.WriteString("}\n")
+	case GraphMermaid:
+		sb.WriteString("graph LR\n")
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", mermaidId(k), mermaidEscape(nodeLabel(k, summaries[k], ": ")))
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&sb, "  %s -->|%s| %s\n", mermaidId(e.From), mermaidEscape(e.Label), mermaidId(e.To))
+		}
+	default:
+		return "", fmt.Errorf("Graphformat %s ist ungültig (%s, %s)", format, GraphDOT, GraphMermaid)
+	}
+	return sb.String(), nil
+}
+
+func nodeLabel(key, summary, sep string) string {
+	if summary == "" {
+		return key
+	}
+	return key + sep + summary
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func mermaidId(key string) string {
+	return strings.NewReplacer("-", "_", " ", "_").Replace(key)
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testLinkTypes = []IssueLinkType{
+	{Id: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+	{Id: "10003", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+}
+
+func TestPlanLinks(t *testing.T) {
+	blocks := IssueLinkType{Id: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+	c := &TestRestClient{
+		linkTypes: testLinkTypes,
+		issues: []Issue{{Key: "REL-1", Fields: map[string]interface{}{"issuelinks": []interface{}{
+			map[string]interface{}{"id": "1", "type": blocks, "inwardIssue": map[string]interface{}{"key": "DEMO-1"}},
+			map[string]interface{}{"id": "2", "type": blocks, "outwardIssue": map[string]interface{}{"key": "DEMO-2"}},
+		}}}},
+	}
+	tests := []struct {
+		testcase string
+		phrase   string
+		results  []LinkResult
+		links    []IssueLink
+	}{
+		{
+			"inward description",
+			"is blocked by",
+			[]LinkResult{{Key: "DEMO-1", Skipped: true}, {Key: "DEMO-2"}, {Key: "REL-1", Skipped: true}},
+			[]IssueLink{{Type: IssueLinkType{Name: "Blocks"}, InwardIssue: &LinkedIssue{Key: "DEMO-2"}, OutwardIssue: &LinkedIssue{Key: "REL-1"}}},
+		},
+		{
+			"outward description",
+			"Blocks",
+			[]LinkResult{{Key: "DEMO-1"}, {Key: "DEMO-2", Skipped: true}, {Key: "REL-1", Skipped: true}},
+			[]IssueLink{{Type: IssueLinkType{Name: "Blocks"}, InwardIssue: &LinkedIssue{Key: "REL-1"}, OutwardIssue: &LinkedIssue{Key: "DEMO-1"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c.links = nil
+			plans, err := PlanLinks("REL-1", []string{"DEMO-1", "DEMO-2", "REL-1"}, tt.phrase, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results := ApplyLinks(plans, c)
+			if !reflect.DeepEqual(results, tt.results) {
+				t.Errorf("got: %+v - want: %+v", results, tt.results)
+			}
+			if !reflect.DeepEqual(c.links, tt.links) {
+				t.Errorf("got: %+v - want: %+v", c.links, tt.links)
+			}
+		})
+	}
+	plans, err := PlanLinks("REL-1", []string{"DEMO-2"}, "is blocked by", c)
+	want := Change{Project: "DEMO", Object: "Vorgang DEMO-2", Operation: OpLink, State: "blocks REL-1"}
+	if err != nil || len(plans) != 1 || plans[0].Change() != want || !Destructive([]Change{want}) {
+		t.Errorf("got: %v %+v - want: %v", err, plans, want)
+	}
+	if _, err := PlanLinks("REL-1", []string{"DEMO-1"}, "depends on", c); err == nil {
+		t.Errorf("got: no error - want: error for unknown link type")
+	}
+}
+
+func TestWriteGraph(t *testing.T) {
+	blocks := map[string]interface{}{"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}
+	issues := []Issue{
+		{Key: "REL-1", Fields: map[string]interface{}{"summary": `Plattform "2021-07"`, "issuelinks": []interface{}{
+			map[string]interface{}{"type": blocks, "inwardIssue": map[string]interface{}{"key": "DEMO-2", "fields": map[string]interface{}{"summary": "Suche"}}},
+			map[string]interface{}{"type": blocks, "inwardIssue": map[string]interface{}{"key": "DEMO-1", "fields": map[string]interface{}{"summary": "Anmeldung"}}},
+		}}},
+		{Key: "DEMO-2", Fields: map[string]interface{}{"summary": "Suche", "issuelinks": []interface{}{
+			map[string]interface{}{"type": blocks, "outwardIssue": map[string]interface{}{"key": "REL-1"}},
+		}}},
+	}
+	edges, summaries := LinkGraph(issues)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{GraphDOT, `digraph links {
+  rankdir=LR;
+  node [shape=box];
+  "DEMO-1" [label="DEMO-1\nAnmeldung"];
+  "DEMO-2" [label="DEMO-2\nSuche"];
+  "REL-1" [label="REL-1\nPlattform \"2021-07\""];
+  "DEMO-1" -> "REL-1" [label="blocks"];
+  "DEMO-2" -> "REL-1" [label="blocks"];
+}
+`},
+		{GraphMermaid, `graph LR
+  DEMO_1["DEMO-1: Anmeldung"]
+  DEMO_2["DEMO-2: Suche"]
+  REL_1["REL-1: Plattform #quot;2021-07#quot;"]
+  DEMO_1 -->|blocks| REL_1
+  DEMO_2 -->|blocks| REL_1
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			graph, err := WriteGraph(tt.format, edges, summaries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if graph != tt.want {
+				t.Errorf("got:\n%s\n- want:\n%s", graph, tt.want)
+			}
+		})
+	}
+	if _, err := WriteGraph("svg", edges, summaries); err == nil {
+		t.Errorf("got: no error - want: error for unknown format")
+	}
+}
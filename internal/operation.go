@@ -0,0 +1,62 @@
+package internal
+
+import "fmt"
+
+type Operation string
+
+const (
+	OpCreate  Operation = "anlegen"
+	OpUpdate  Operation = "aktualisieren"
+	OpMove    Operation = "verschieben"
+	OpRelease Operation = "releasen"
+	OpArchive Operation = "archivieren"
+	OpDelete  Operation = "löschen"
+	OpStart   Operation = "starten"
+	OpClose   Operation = "abschließen"
+
+	OpTransition Operation = "überführen"
+	OpComment    Operation = "kommentieren"
+	OpEditLabels Operation = "Labels ändern"
+	OpLink       Operation = "verknüpfen"
+)
+
+// destructiveOperations need a confirmation, changes of issues are not journaled and cannot
+// be undone.
+var destructiveOperations = map[Operation]bool{
+	OpRelease:    true,
+	OpArchive:    true,
+	OpDelete:     true,
+	OpClose:      true,
+	OpTransition: true,
+	OpComment:    true,
+	OpEditLabels: true,
+	OpLink:       true,
+}
+
+func (op Operation) Destructive() bool {
+	return destructiveOperations[op]
+}
+
+type Change struct {
+	Project   string
+	Object    string
+	Operation Operation
+	State     string
+}
+
+func (ch Change) String() string {
+	s := fmt.Sprintf("Projekt %s: %s %s", ch.Project, ch.Object, ch.Operation)
+	if ch.State != "" {
+		s += fmt.Sprintf(" (%s)", ch.State)
+	}
+	return s
+}
+
+func Destructive(changes []Change) bool {
+	for _, ch := range changes {
+		if ch.Operation.Destructive() {
+			return true
+		}
+	}
+	return false
+}
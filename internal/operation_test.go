@@ -0,0 +1,41 @@
+package internal
+
+import "testing"
+
+func TestDestructive(t *testing.T) {
+	tests := []struct {
+		testcase    string
+		changes     []Change
+		destructive bool
+	}{
+		{"no changes", nil, false},
+		{"create and update", []Change{{Operation: OpCreate}, {Operation: OpUpdate}, {Operation: OpMove}}, false},
+		{"release", []Change{{Operation: OpCreate}, {Operation: OpRelease}}, true},
+		{"archive", []Change{{Operation: OpArchive}}, true},
+		{"delete", []Change{{Operation: OpDelete}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			if Destructive(tt.changes) != tt.destructive {
+				t.Errorf("got: %v - want: %v", Destructive(tt.changes), tt.destructive)
+			}
+		})
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	tests := []struct {
+		change Change
+		str    string
+	}{
+		{Change{Project: "DB", Object: "Version 2021-07", Operation: OpRelease, State: "released, Release Datum 2021-07-06"}, "Projekt DB: Version 2021-07 releasen (released, Release Datum 2021-07-06)"},
+		{Change{Project: "DB", Object: "Komponente backend", Operation: OpDelete}, "Projekt DB: Komponente backend löschen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if tt.change.String() != tt.str {
+				t.Errorf("got: %s - want: %s", tt.change.String(), tt.str)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable    = "table"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+func WriteTable(w io.Writer, format string, header []string, rows [][]string) error {
+	switch format {
+	case FormatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write(header)
+		_ = cw.WriteAll(rows)
+		return cw.Error()
+	case FormatJSON:
+		records := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			rec := make(map[string]string)
+			for i, h := range header {
+				if i < len(row) {
+					rec[h] = row[i]
+				}
+			}
+			records = append(records, rec)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatMarkdown:
+		_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(markdownCells(header), " | "))
+		_, _ = fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(header)))
+		for _, row := range rows {
+			_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(markdownCells(row), " | "))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("Ausgabeformat %s wird nicht unterstützt", format)
+	}
+}
+
+func WriteIssues(w io.Writer, format string, issues []Issue, fields []string) error {
+	header := append([]string{"key"}, fields...)
+	rows := make([][]string, 0, len(issues))
+	for _, i := range issues {
+		row := []string{i.Key}
+		for _, f := range fields {
+			row = append(row, i.Field(f))
+		}
+		rows = append(rows, row)
+	}
+	return WriteTable(w, format, header, rows)
+}
+
+func markdownCells(row []string) []string {
+	cells := make([]string, 0, len(row))
+	for _, c := range row {
+		c = strings.ReplaceAll(c, "|", "\\|")
+		cells = append(cells, strings.ReplaceAll(c, "\n", " "))
+	}
+	return cells
+}
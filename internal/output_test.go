@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteIssues(t *testing.T) {
+	issues := []Issue{
+		{Key: "DB-1", Fields: map[string]interface{}{"summary": "Deploy", "status": map[string]interface{}{"name": "Done"}}},
+		{Key: "DB-2", Fields: map[string]interface{}{"summary": "Smoke, Test", "status": map[string]interface{}{"name": "To Do"}}},
+	}
+	tests := []struct {
+		testcase string
+		format   string
+		output   string
+		err      bool
+	}{
+		{
+			"table",
+			FormatTable,
+			"key   summary      status\nDB-1  Deploy       Done\nDB-2  Smoke, Test  To Do\n",
+			false,
+		},
+		{
+			"csv",
+			FormatCSV,
+			"key,summary,status\nDB-1,Deploy,Done\nDB-2,\"Smoke, Test\",To Do\n",
+			false,
+		},
+		{
+			"json",
+			FormatJSON,
+			"[\n  {\n    \"key\": \"DB-1\",\n    \"status\": \"Done\",\n    \"summary\": \"Deploy\"\n  },\n  {\n    \"key\": \"DB-2\",\n    \"status\": \"To Do\",\n    \"summary\": \"Smoke, Test\"\n  }\n]\n",
+			false,
+		},
+		{
+			"markdown",
+			FormatMarkdown,
+			"| key | summary | status |\n|---|---|---|\n| DB-1 | Deploy | Done |\n| DB-2 | Smoke, Test | To Do |\n",
+			false,
+		},
+		{
+			"unknown format",
+			"xml",
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := WriteIssues(buf, tt.format, issues, []string{"summary", "status"})
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case buf.String() != tt.output:
+				t.Errorf("got: %q - want: %q", buf.String(), tt.output)
+			}
+		})
+	}
+}
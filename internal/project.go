@@ -1,8 +1,9 @@
 package internal
 
 type Project struct {
-	Id          string    `json:"id"`
-	Key         string    `json:"key"`
-	Description string    `json:"description"`
-	Versions    []Version `json:"versions"`
+	Id          string      `json:"id"`
+	Key         string      `json:"key"`
+	Description string      `json:"description"`
+	Versions    []Version   `json:"versions"`
+	Components  []Component `json:"components"`
 }
@@ -0,0 +1,375 @@
+package internal
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatHTML = "html"
+
+	reportDateLayout   = "2006-01-02"
+	issueTimeLayout    = "2006-01-02T15:04:05.000-0700"
+	maxBurndownDays    = 366
+	secondsPerHour     = 3600
+	statusCategoryDone = "done"
+	statusCategoryNew  = "new"
+)
+
+// estimateFields are time tracking fields in seconds, reported in hours.
+var estimateFields = map[string]bool{
+	"timeoriginalestimate":          true,
+	"timeestimate":                  true,
+	"aggregatetimeoriginalestimate": true,
+	"aggregatetimeestimate":         true,
+}
+
+type VersionProgress struct {
+	Project    string
+	Version    Version
+	Total      int
+	Done       int
+	InProgress int
+	ToDo       int
+	Points     float64
+	DonePoints float64
+	// DaysLeft is nil for released versions and versions without release date
+	DaysLeft *int
+	Overdue  bool
+	Burndown []BurndownDay
+}
+
+// BurndownDay holds the issues and points not done at the end of a day.
+type BurndownDay struct {
+	Date      string
+	Remaining int
+	Points    float64
+}
+
+// PointsUnit names the unit of the points field, estimates are summed in hours.
+func PointsUnit(pointsField string) string {
+	if estimateFields[pointsField] {
+		return "stunden"
+	}
+	return "punkte"
+}
+
+// VersionReport aggregates the issues of the version by status category and sums the
+// points field, e.g. customfield_10016 for story points or timeoriginalestimate.
+func VersionReport(prj *Project, verName, pointsField string, today time.Time, c RestClient) (*VersionProgress, error) {
+	ver, err := getVersion(prj, verName)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{"status", "created", "resolutiondate"}
+	if pointsField != "" {
+		fields = append(fields, pointsField)
+	}
+	issues, err := SearchIssues(fmt.Sprintf("fixVersion = %s ORDER BY key", ver.Id), fields, c)
+	if err != nil {
+		return nil, err
+	}
+	return versionProgress(prj.Key, *ver, issues, pointsField, today), nil
+}
+
+func versionProgress(prjKey string, ver Version, issues []Issue, pointsField string, today time.Time) *VersionProgress {
+	today = day(today)
+	p := &VersionProgress{Project: prjKey, Version: ver, Total: len(issues)}
+	for _, i := range issues {
+		points := issuePoints(i, pointsField)
+		p.Points += points
+		switch statusCategory(i) {
+		case statusCategoryDone:
+			p.Done++
+			p.DonePoints += points
+		case statusCategoryNew:
+			p.ToDo++
+		default:
+			p.InProgress++
+		}
+	}
+	if ver.ReleaseDate != nil && !ver.Released {
+		if release, err := time.ParseInLocation(reportDateLayout, *ver.ReleaseDate, today.Location()); err == nil {
+			days := int(math.Round(release.Sub(today).Hours() / 24))
+			p.DaysLeft = &days
+			p.Overdue = days < 0
+		}
+	}
+	p.Burndown = burndown(ver, issues, pointsField, today)
+	return p
+}
+
+// burndown counts the issues created and not done at the end of each day from the start
+// of the version, or its first issue, until today or the release of a released version,
+// for at most a year.
+func burndown(ver Version, issues []Issue, pointsField string, today time.Time) []BurndownDay {
+	type span struct {
+		created, resolved time.Time
+		points            float64
+	}
+	var spans []span
+	var start time.Time
+	for _, i := range issues {
+		s := span{created: issueDay(i, "created", today), points: issuePoints(i, pointsField)}
+		if statusCategory(i) == statusCategoryDone {
+			s.resolved = issueDay(i, "resolutiondate", today)
+		}
+		if start.IsZero() || s.created.Before(start) {
+			start = s.created
+		}
+		spans = append(spans, s)
+	}
+	if ver.StartDate != nil {
+		if vs, err := time.ParseInLocation(reportDateLayout, *ver.StartDate, today.Location()); err == nil {
+			start = vs
+		}
+	}
+	end := today
+	if ver.Released && ver.ReleaseDate != nil {
+		if release, err := time.ParseInLocation(reportDateLayout, *ver.ReleaseDate, today.Location()); err == nil && release.Before(end) {
+			end = release
+		}
+	}
+	if start.IsZero() || start.After(end) {
+		return nil
+	}
+	if end.Sub(start) > maxBurndownDays*24*time.Hour {
+		end = start.AddDate(0, 0, maxBurndownDays)
+	}
+	var days []BurndownDay
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		bd := BurndownDay{Date: d.Format(reportDateLayout)}
+		for _, s := range spans {
+			if s.created.After(d) || (!s.resolved.IsZero() && !s.resolved.After(d)) {
+				continue
+			}
+			bd.Remaining++
+			bd.Points += s.points
+		}
+		days = append(days, bd)
+	}
+	return days
+}
+
+func statusCategory(i Issue) string {
+	status, _ := i.Fields["status"].(map[string]interface{})
+	category, _ := status["statusCategory"].(map[string]interface{})
+	key, _ := category["key"].(string)
+	return key
+}
+
+func issuePoints(i Issue, pointsField string) float64 {
+	points, _ := i.Fields[pointsField].(float64)
+	if estimateFields[pointsField] {
+		return points / secondsPerHour
+	}
+	return points
+}
+
+// issueDay returns the day of a date-time field, today if it is missing.
+func issueDay(i Issue, field string, today time.Time) time.Time {
+	s, _ := i.Fields[field].(string)
+	t, err := time.Parse(issueTimeLayout, s)
+	if err != nil {
+		return today
+	}
+	return day(t.In(today.Location()))
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (p VersionProgress) PercentDone() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Done * 100 / p.Total
+}
+
+func ProgressTable(reports []VersionProgress, unit string) ([]string, [][]string) {
+	header := []string{"projekt", "version", "stand", "vorgänge", "erledigt", "in arbeit", "offen", "%", unit, unit + " erledigt", "tage bis release", "überfällig"}
+	var rows [][]string
+	for _, p := range reports {
+		var daysLeft, overdue string
+		if p.DaysLeft != nil {
+			daysLeft = strconv.Itoa(*p.DaysLeft)
+		}
+		if p.Overdue {
+			overdue = "ja"
+		}
+		rows = append(rows, []string{
+			p.Project, p.Version.Name, versionCell(p.Version),
+			strconv.Itoa(p.Total), strconv.Itoa(p.Done), strconv.Itoa(p.InProgress), strconv.Itoa(p.ToDo),
+			strconv.Itoa(p.PercentDone()), formatPoints(p.Points), formatPoints(p.DonePoints), daysLeft, overdue,
+		})
+	}
+	return header, rows
+}
+
+func formatPoints(points float64) string {
+	s := strconv.FormatFloat(points, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0")
+}
+
+const (
+	chartWidth  = 640
+	chartHeight = 240
+	chartMargin = 30
+	barWidth    = 400
+)
+
+type chartBar struct {
+	Class string
+	Label string
+	Count int
+	X, W  float64
+}
+
+type progressChart struct {
+	VersionProgress
+	Unit   string
+	Bars   []chartBar
+	Actual string
+	Ideal  string
+	Max    float64
+	First  string
+	Last   string
+}
+
+// WriteProgressHTML writes a self-contained HTML page with a progress bar and a burndown
+// chart of remaining points, or issues without points, per version.
+func WriteProgressHTML(w io.Writer, reports []VersionProgress, unit string, today time.Time) error {
+	var charts []progressChart
+	for _, p := range reports {
+		charts = append(charts, newProgressChart(p, unit))
+	}
+	return progressTemplate.Execute(w, struct {
+		Date   string
+		Charts []progressChart
+	}{today.Format(reportDateLayout), charts})
+}
+
+func newProgressChart(p VersionProgress, unit string) progressChart {
+	pc := progressChart{VersionProgress: p, Unit: unit}
+	x := 0.0
+	for _, b := range []chartBar{
+		{Class: "done", Label: "erledigt", Count: p.Done},
+		{Class: "progress", Label: "in Arbeit", Count: p.InProgress},
+		{Class: "todo", Label: "offen", Count: p.ToDo},
+	} {
+		if p.Total > 0 {
+			w := float64(b.Count) * barWidth / float64(p.Total)
+			b.X, b.W = math.Round(x*10)/10, math.Round(w*10)/10
+			x += w
+		}
+		pc.Bars = append(pc.Bars, b)
+	}
+	if len(p.Burndown) == 0 {
+		return pc
+	}
+	remaining := func(d BurndownDay) float64 {
+		if p.Points > 0 {
+			return d.Points
+		}
+		return float64(d.Remaining)
+	}
+	start, _ := time.Parse(reportDateLayout, p.Burndown[0].Date)
+	end, _ := time.Parse(reportDateLayout, p.Burndown[len(p.Burndown)-1].Date)
+	var release time.Time
+	if p.Version.ReleaseDate != nil {
+		release, _ = time.Parse(reportDateLayout, *p.Version.ReleaseDate)
+		if release.After(end) {
+			end = release
+		}
+	}
+	days := math.Max(end.Sub(start).Hours()/24, 1)
+	for _, d := range p.Burndown {
+		pc.Max = math.Max(pc.Max, remaining(d))
+	}
+	pc.Max = math.Max(pc.Max, 1)
+	xPos := func(t time.Time) float64 {
+		return chartMargin + t.Sub(start).Hours()/24/days*(chartWidth-2*chartMargin)
+	}
+	yPos := func(v float64) float64 {
+		return chartHeight - chartMargin - v/pc.Max*(chartHeight-2*chartMargin)
+	}
+	var points []string
+	for _, d := range p.Burndown {
+		t, _ := time.Parse(reportDateLayout, d.Date)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", xPos(t), yPos(remaining(d))))
+	}
+	pc.Actual = strings.Join(points, " ")
+	if !release.IsZero() && release.After(start) {
+		pc.Ideal = fmt.Sprintf("%.1f,%.1f %.1f,%.1f", xPos(start), yPos(remaining(p.Burndown[0])), xPos(release), yPos(0))
+	}
+	pc.First, pc.Last = start.Format(reportDateLayout), end.Format(reportDateLayout)
+	return pc
+}
+
+var progressTemplate = template.Must(template.New("progress").Funcs(template.FuncMap{
+	"points": formatPoints,
+	"title": func(s string) string {
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<title>Versionsfortschritt {{.Date}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #172b4d; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #dfe1e6; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+section { margin-bottom: 3em; }
+.done { fill: #36b37e; }
+.progress { fill: #0065ff; }
+.todo { fill: #dfe1e6; }
+.overdue { color: #de350b; font-weight: bold; }
+.axis { stroke: #6b778c; }
+.actual { fill: none; stroke: #0065ff; stroke-width: 2; }
+.ideal { fill: none; stroke: #6b778c; stroke-dasharray: 4 4; }
+svg text { font-size: 11px; fill: #6b778c; }
+</style>
+</head>
+<body>
+<h1>Versionsfortschritt {{.Date}}</h1>
+{{- range .Charts}}
+<section>
+<h2>{{.Project}} {{.Version.Name}}{{if .Version.Released}} (released){{end}}{{if .Overdue}} <span class="overdue">überfällig</span>{{end}}</h2>
+<table>
+<tr><th>Vorgänge</th><th>erledigt</th><th>in Arbeit</th><th>offen</th><th>{{title .Unit}}</th><th>{{title .Unit}} erledigt</th><th>Release</th><th>Tage bis Release</th></tr>
+<tr><td>{{.Total}}</td><td>{{.Done}}</td><td>{{.InProgress}}</td><td>{{.ToDo}}</td><td>{{points .Points}}</td><td>{{points .DonePoints}}</td><td>{{with .Version.ReleaseDate}}{{.}}{{end}}</td><td>{{with .DaysLeft}}{{.}}{{end}}</td></tr>
+</table>
+<svg width="520" height="24" role="img" aria-label="{{.PercentDone}}% erledigt">
+{{- range .Bars}}{{if .Count}}
+<rect class="{{.Class}}" x="{{.X}}" y="0" width="{{.W}}" height="24"><title>{{.Label}}: {{.Count}}</title></rect>
+{{- end}}{{end}}
+<text x="410" y="16">{{.PercentDone}}% erledigt</text>
+</svg>
+{{- if .Actual}}
+<h3>Burndown ({{if .Points}}{{title .Unit}}{{else}}Vorgänge{{end}})</h3>
+<svg width="640" height="240" role="img" aria-label="Burndown">
+<line class="axis" x1="30" y1="210" x2="610" y2="210"/>
+<line class="axis" x1="30" y1="30" x2="30" y2="210"/>
+<text x="2" y="34">{{points .Max}}</text>
+<text x="2" y="214">0</text>
+<text x="30" y="228">{{.First}}</text>
+<text x="548" y="228">{{.Last}}</text>
+{{- if .Ideal}}
+<polyline class="ideal" points="{{.Ideal}}"/>
+{{- end}}
+<polyline class="actual" points="{{.Actual}}"/>
+</svg>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reportIssue(key, category, created, resolved string, points float64) Issue {
+	fields := map[string]interface{}{
+		"status":            map[string]interface{}{"statusCategory": map[string]interface{}{"key": category}},
+		"created":           created + "T09:00:00.000+0100",
+		"customfield_10016": points,
+	}
+	if resolved != "" {
+		fields["resolutiondate"] = resolved + "T17:00:00.000+0100"
+	}
+	return Issue{Key: key, Fields: fields}
+}
+
+func TestVersionReport(t *testing.T) {
+	start, release := "2021-03-01", "2021-03-05"
+	c := &TestRestClient{
+		versions: []Version{{Id: "10001", Name: "1.1.0", StartDate: &start, ReleaseDate: &release}},
+		issues: []Issue{
+			reportIssue("DEMO-2", statusCategoryDone, "2021-03-01", "2021-03-02", 5),
+			reportIssue("DEMO-3", "indeterminate", "2021-03-01", "", 8),
+			reportIssue("DEMO-4", statusCategoryNew, "2021-03-03", "", 2),
+		},
+	}
+	prj, _ := c.GetProject("DEMO")
+	today := time.Date(2021, 3, 4, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	p, err := VersionReport(prj, "1.1.0", "customfield_10016", today, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 3 || p.Done != 1 || p.InProgress != 1 || p.ToDo != 1 || p.Points != 15 || p.DonePoints != 5 {
+		t.Errorf("got: %+v - want: 3 issues with 1 done, 1 in progress, 1 to do and 5 of 15 points", p)
+	}
+	if p.DaysLeft == nil || *p.DaysLeft != 1 || p.Overdue {
+		t.Errorf("got: days left %v, overdue %v - want: 1 day left", p.DaysLeft, p.Overdue)
+	}
+	burndown := []BurndownDay{
+		{"2021-03-01", 2, 13},
+		{"2021-03-02", 1, 8},
+		{"2021-03-03", 2, 10},
+		{"2021-03-04", 2, 10},
+	}
+	if !reflect.DeepEqual(p.Burndown, burndown) {
+		t.Errorf("got: %+v - want: %+v", p.Burndown, burndown)
+	}
+
+	p, _ = VersionReport(prj, "1.1.0", "customfield_10016", today.AddDate(0, 0, 3), c)
+	if p.DaysLeft == nil || *p.DaysLeft != -2 || !p.Overdue {
+		t.Errorf("got: days left %v, overdue %v - want: overdue since 2 days", p.DaysLeft, p.Overdue)
+	}
+	if _, err := VersionReport(prj, "2.0.0", "customfield_10016", today, c); err == nil {
+		t.Errorf("got: no error - want: error for missing version")
+	}
+}
+
+func TestProgressTable(t *testing.T) {
+	release := "2021-04-01"
+	days := -3
+	issues := []Issue{
+		{Key: "DEMO-1", Fields: map[string]interface{}{"timeoriginalestimate": float64(5400)}},
+	}
+	p := *versionProgress("DEMO", Version{Name: "1.1.0", ReleaseDate: &release}, issues, "timeoriginalestimate", time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC))
+	if p.DaysLeft == nil || *p.DaysLeft != days {
+		t.Fatalf("got: days left %v - want: %d", p.DaysLeft, days)
+	}
+	header, rows := ProgressTable([]VersionProgress{p}, PointsUnit("timeoriginalestimate"))
+	want := [][]string{{"DEMO", "1.1.0", "offen 2021-04-01", "1", "0", "1", "0", "0", "1.5", "0", "-3", "ja"}}
+	if header[8] != "stunden" || !reflect.DeepEqual(rows, want) {
+		t.Errorf("got: %v %v - want: stunden %v", header, rows, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteProgressHTML(&buf, []VersionProgress{p}, "stunden", time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"<h2>DEMO 1.1.0 <span class=\"overdue\">überfällig</span></h2>", `<rect class="progress" x="0" y="0" width="400"`, "<th>Stunden erledigt</th>"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("got: %s - want: %s", buf.String(), s)
+		}
+	}
+}
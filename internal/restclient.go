@@ -7,17 +7,46 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 type RestClient interface {
 	GetProject(prjKey string) (*Project, error)
 	CreateVersion(version Version) error
 	UpdateVersion(version Version) error
+	DeleteVersion(version Version) error
+	MoveVersion(verId, position string) error
+	GetComponents(prjKey string) ([]Component, error)
+	CreateComponent(component Component) error
+	UpdateComponent(component Component) error
+	DeleteComponent(compId, moveIssuesTo string) error
+	GetVersionRelatedIssueCounts(verId string) (*VersionRelatedIssueCounts, error)
+	GetVersionUnresolvedIssueCount(verId string) (*VersionUnresolvedIssueCount, error)
+	SearchIssues(jql string, fields []string, startAt, maxResults int) (*SearchResult, error)
+	GetIssue(issueKey string, fields []string) (*Issue, error)
+	EditIssue(issueKey string, update IssueUpdate) error
+	CreateIssue(issue IssueUpdate) (*Issue, error)
+	AddComment(issueKey string, comment Comment) error
+	GetIssueLinkTypes() ([]IssueLinkType, error)
+	CreateIssueLink(link IssueLink) error
+	GetTransitions(issueKey string) ([]Transition, error)
+	TransitionIssue(issueKey, transitionId string, fields map[string]interface{}) error
+	GetBoards(prjKey string) ([]Board, error)
+	GetSprints(boardId int) ([]Sprint, error)
+	CreateSprint(sprint Sprint) (*Sprint, error)
+	UpdateSprint(sprint Sprint) error
+	MoveIssuesToSprint(sprintId int, issueKeys []string) error
 }
 
+const agileMoveLimit = 50
+
 type JiraRestClient struct {
 	BaseURL    *url.URL
 	HttpClient *http.Client
+	Audit      *AuditLog
+	Cache      *Cache
+	auditUser  *User
 }
 
 func CreateRestClient(userinfo *url.Userinfo, u *url.URL) (*JiraRestClient, error) {
@@ -68,6 +97,369 @@ func (c *JiraRestClient) UpdateVersion(version Version) error {
 	return err
 }
 
+func (c *JiraRestClient) DeleteVersion(version Version) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/version/%s", version.Id)}
+	req, err := c.createRestRequest(rel, "DELETE", nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Version %s ist nicht vorhanden", version.Name)
+	}
+	return err
+}
+
+func (c *JiraRestClient) MoveVersion(verId, position string) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/version/%s/move", verId)}
+	req, err := c.createRestRequest(rel, "POST", map[string]string{"position": position})
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Version %s kann nicht verschoben werden", verId)
+	}
+	return err
+}
+
+func (c *JiraRestClient) GetComponents(prjKey string) ([]Component, error) {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/project/%s/components", prjKey)}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	var comps []Component
+	_, err = c.call(req, &comps)
+	return comps, err
+}
+
+func (c *JiraRestClient) CreateComponent(component Component) error {
+	rel := &url.URL{Path: "/rest/api/3/component"}
+	req, err := c.createRestRequest(rel, "POST", component)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, &component)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Komponente %s kann nicht angelegt werden", component.Name)
+	}
+	return err
+}
+
+func (c *JiraRestClient) UpdateComponent(component Component) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/component/%s", component.Id)}
+	req, err := c.createRestRequest(rel, "PUT", component)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, &component)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Komponente %s kann nicht aktualisiert werden", component.Name)
+	}
+	return err
+}
+
+func (c *JiraRestClient) DeleteComponent(compId, moveIssuesTo string) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/component/%s", compId)}
+	if moveIssuesTo != "" {
+		rel.RawQuery = url.Values{"moveIssuesTo": {moveIssuesTo}}.Encode()
+	}
+	req, err := c.createRestRequest(rel, "DELETE", nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Komponente %s ist nicht vorhanden", compId)
+	}
+	return err
+}
+
+func (c *JiraRestClient) GetVersionRelatedIssueCounts(verId string) (*VersionRelatedIssueCounts, error) {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/version/%s/relatedIssueCounts", verId)}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	counts := &VersionRelatedIssueCounts{}
+	_, err = c.call(req, counts)
+	return counts, err
+}
+
+func (c *JiraRestClient) GetVersionUnresolvedIssueCount(verId string) (*VersionUnresolvedIssueCount, error) {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/version/%s/unresolvedIssueCount", verId)}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	count := &VersionUnresolvedIssueCount{}
+	_, err = c.call(req, count)
+	return count, err
+}
+
+func (c *JiraRestClient) SearchIssues(jql string, fields []string, startAt, maxResults int) (*SearchResult, error) {
+	q := url.Values{}
+	q.Set("jql", jql)
+	q.Set("fields", strings.Join(fields, ","))
+	q.Set("startAt", strconv.Itoa(startAt))
+	q.Set("maxResults", strconv.Itoa(maxResults))
+	rel := &url.URL{Path: "/rest/api/3/search", RawQuery: q.Encode()}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	res := &SearchResult{}
+	_, err = c.call(req, res)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return nil, fmt.Errorf("Suche '%s' ist ungültig", jql)
+	}
+	return res, err
+}
+
+func (c *JiraRestClient) GetIssue(issueKey string, fields []string) (*Issue, error) {
+	q := url.Values{}
+	q.Set("fields", strings.Join(fields, ","))
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/issue/%s", issueKey), RawQuery: q.Encode()}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	issue := &Issue{}
+	_, err = c.call(req, issue)
+	return issue, err
+}
+
+func (c *JiraRestClient) EditIssue(issueKey string, update IssueUpdate) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/issue/%s", issueKey)}
+	req, err := c.createRestRequest(rel, "PUT", update)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Vorgang %s kann nicht bearbeitet werden", issueKey)
+	}
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Vorgang %s ist nicht vorhanden", issueKey)
+	}
+	return err
+}
+
+func (c *JiraRestClient) CreateIssue(issue IssueUpdate) (*Issue, error) {
+	rel := &url.URL{Path: "/rest/api/3/issue"}
+	req, err := c.createRestRequest(rel, "POST", issue)
+	if err != nil {
+		return nil, err
+	}
+	created := &Issue{}
+	_, err = c.call(req, created)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return nil, fmt.Errorf("Vorgang %s kann nicht angelegt werden (%s)", fieldString(issue.Fields["summary"]), err)
+	}
+	return created, err
+}
+
+func (c *JiraRestClient) AddComment(issueKey string, comment Comment) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/issue/%s/comment", issueKey)}
+	req, err := c.createRestRequest(rel, "POST", comment)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Kommentar zu Vorgang %s kann nicht angelegt werden (%s)", issueKey, err)
+	}
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Vorgang %s ist nicht vorhanden", issueKey)
+	}
+	return err
+}
+
+func (c *JiraRestClient) GetIssueLinkTypes() ([]IssueLinkType, error) {
+	rel := &url.URL{Path: "/rest/api/3/issueLinkType"}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	res := struct {
+		IssueLinkTypes []IssueLinkType `json:"issueLinkTypes"`
+	}{}
+	_, err = c.call(req, &res)
+	return res.IssueLinkTypes, err
+}
+
+func (c *JiraRestClient) CreateIssueLink(link IssueLink) error {
+	rel := &url.URL{Path: "/rest/api/3/issueLink"}
+	req, err := c.createRestRequest(rel, "POST", link)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && (t.Status() == http.StatusBadRequest || t.Status() == http.StatusNotFound) {
+		return fmt.Errorf("Verknüpfung %s von %s zu %s kann nicht angelegt werden (%s)", link.Type.Name, link.InwardIssue.Key, link.OutwardIssue.Key, err)
+	}
+	return err
+}
+
+func (c *JiraRestClient) GetTransitions(issueKey string) ([]Transition, error) {
+	q := url.Values{}
+	q.Set("expand", "transitions.fields")
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/issue/%s/transitions", issueKey), RawQuery: q.Encode()}
+	req, err := c.createGetRequest(rel)
+	if err != nil {
+		return nil, err
+	}
+	res := &struct {
+		Transitions []Transition `json:"transitions"`
+	}{}
+	_, err = c.call(req, res)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusNotFound {
+		return nil, fmt.Errorf("Vorgang %s ist nicht vorhanden", issueKey)
+	}
+	return res.Transitions, err
+}
+
+func (c *JiraRestClient) TransitionIssue(issueKey, transitionId string, fields map[string]interface{}) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/api/3/issue/%s/transitions", issueKey)}
+	body := map[string]interface{}{"transition": map[string]string{"id": transitionId}}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	req, err := c.createRestRequest(rel, "POST", body)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Übergang %s für Vorgang %s nicht möglich", transitionId, issueKey)
+	}
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Vorgang %s ist nicht vorhanden", issueKey)
+	}
+	return err
+}
+
+func (c *JiraRestClient) GetBoards(prjKey string) ([]Board, error) {
+	var boards []Board
+	err := c.getAgilePages("/rest/agile/1.0/board", url.Values{"projectKeyOrId": {prjKey}}, func(page *agilePage) error {
+		var values []Board
+		err := json.Unmarshal(page.Values, &values)
+		boards = append(boards, values...)
+		return err
+	})
+	return boards, err
+}
+
+func (c *JiraRestClient) GetSprints(boardId int) ([]Sprint, error) {
+	var sprints []Sprint
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardId)
+	err := c.getAgilePages(path, url.Values{}, func(page *agilePage) error {
+		var values []Sprint
+		err := json.Unmarshal(page.Values, &values)
+		sprints = append(sprints, values...)
+		return err
+	})
+	return sprints, err
+}
+
+func (c *JiraRestClient) CreateSprint(sprint Sprint) (*Sprint, error) {
+	rel := &url.URL{Path: "/rest/agile/1.0/sprint"}
+	req, err := c.createRestRequest(rel, "POST", sprint)
+	if err != nil {
+		return nil, err
+	}
+	created := &Sprint{}
+	_, err = c.call(req, created)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return nil, fmt.Errorf("Sprint %s kann nicht angelegt werden", sprint.Name)
+	}
+	return created, err
+}
+
+func (c *JiraRestClient) UpdateSprint(sprint Sprint) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/agile/1.0/sprint/%d", sprint.Id)}
+	req, err := c.createRestRequest(rel, "POST", sprint)
+	if err != nil {
+		return err
+	}
+	_, err = c.call(req, nil)
+	t, ok := err.(RestError)
+	if ok && t.Status() == http.StatusBadRequest {
+		return fmt.Errorf("Sprint %d kann nicht geändert werden", sprint.Id)
+	}
+	if ok && t.Status() == http.StatusNotFound {
+		return fmt.Errorf("Sprint %d ist nicht vorhanden", sprint.Id)
+	}
+	return err
+}
+
+func (c *JiraRestClient) MoveIssuesToSprint(sprintId int, issueKeys []string) error {
+	rel := &url.URL{Path: fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintId)}
+	for start := 0; start < len(issueKeys); start += agileMoveLimit {
+		end := start + agileMoveLimit
+		if end > len(issueKeys) {
+			end = len(issueKeys)
+		}
+		req, err := c.createRestRequest(rel, "POST", map[string][]string{"issues": issueKeys[start:end]})
+		if err != nil {
+			return err
+		}
+		_, err = c.call(req, nil)
+		t, ok := err.(RestError)
+		if ok && t.Status() == http.StatusBadRequest {
+			return fmt.Errorf("Vorgänge können nicht in Sprint %d verschoben werden", sprintId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type agilePage struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	IsLast     bool            `json:"isLast"`
+	Values     json.RawMessage `json:"values"`
+}
+
+func (c *JiraRestClient) getAgilePages(path string, q url.Values, values func(page *agilePage) error) error {
+	for startAt := 0; ; {
+		q.Set("startAt", strconv.Itoa(startAt))
+		rel := &url.URL{Path: path, RawQuery: q.Encode()}
+		req, err := c.createGetRequest(rel)
+		if err != nil {
+			return err
+		}
+		page := &agilePage{}
+		_, err = c.call(req, page)
+		if err != nil {
+			return err
+		}
+		if err := values(page); err != nil {
+			return err
+		}
+		if page.IsLast || page.MaxResults == 0 {
+			return nil
+		}
+		startAt = page.StartAt + page.MaxResults
+	}
+}
+
 func (c *JiraRestClient) createGetRequest(url *url.URL) (*http.Request, error) {
 	u := c.BaseURL.ResolveReference(url)
 	req, err := http.NewRequest("GET", u.String(), nil)
@@ -81,9 +473,11 @@ func (c *JiraRestClient) createGetRequest(url *url.URL) (*http.Request, error) {
 func (c *JiraRestClient) createRestRequest(url *url.URL, method string, body interface{}) (*http.Request, error) {
 	u := c.BaseURL.ResolveReference(url)
 	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(body)
-	if err != nil {
-		return nil, err
+	if body != nil {
+		err := json.NewEncoder(buf).Encode(body)
+		if err != nil {
+			return nil, err
+		}
 	}
 	req, err := http.NewRequest(method, u.String(), buf)
 	if err != nil {
@@ -95,6 +489,36 @@ func (c *JiraRestClient) createRestRequest(url *url.URL, method string, body int
 }
 
 func (c *JiraRestClient) call(req *http.Request, v interface{}) (*http.Response, error) {
+	if c.Cache != nil && req.Method == http.MethodGet {
+		return c.doCached(req, v)
+	}
+	if c.Audit != nil && req.Method != http.MethodGet {
+		if c.auditUser == nil {
+			return nil, fmt.Errorf("Jira Benutzer für das Audit-Log %s ist nicht bekannt", c.Audit.Path)
+		}
+		if err := c.Audit.failed(); err != nil {
+			return nil, fmt.Errorf("Audit-Log %s ist fehlerhaft, %s %s wird nicht gesendet (%s)", c.Audit.Path, req.Method, req.URL.Path, err)
+		}
+	}
+	resp, err := c.do(req, v)
+	if c.Cache != nil && req.Method != http.MethodGet {
+		cacheErr := c.Cache.Invalidate(req.URL.Path)
+		if cacheErr != nil && err == nil {
+			err = fmt.Errorf("Cache kann nicht aktualisiert werden (%s)", cacheErr)
+		}
+	}
+	if c.Audit != nil && req.Method != http.MethodGet {
+		auditErr := c.audit(req, resp, err)
+		if auditErr != nil && err == nil {
+			err = auditErr
+		} else if auditErr != nil {
+			err = fmt.Errorf("%s, %s", err, auditErr)
+		}
+	}
+	return resp, err
+}
+
+func (c *JiraRestClient) do(req *http.Request, v interface{}) (*http.Response, error) {
 	resp, err := c.HttpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -102,10 +526,69 @@ func (c *JiraRestClient) call(req *http.Request, v interface{}) (*http.Response,
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
-	err = json.NewDecoder(resp.Body).Decode(v)
+	if v != nil {
+		err = json.NewDecoder(resp.Body).Decode(v)
+		if err == io.EOF {
+			err = nil
+		}
+	}
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		restErr := RestError{resp.Status, resp.StatusCode}
 		return resp, restErr
 	}
 	return resp, err
 }
+
+func (c *JiraRestClient) doCached(req *http.Request, v interface{}) (*http.Response, error) {
+	user := c.BaseURL.User.Username()
+	u := *req.URL
+	u.User = nil
+	entry := c.Cache.Get(user, u.String())
+	if entry != nil && c.Cache.Fresh(entry) {
+		return cachedResponse(req, entry, v)
+	}
+	if entry != nil && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = c.Cache.Put(entry)
+		return cachedResponse(req, entry, v)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, RestError{resp.Status, resp.StatusCode}
+	}
+	err = c.Cache.Put(&CacheEntry{User: user, URL: u.String(), ETag: resp.Header.Get("ETag"), Body: string(body)})
+	if err != nil {
+		return resp, fmt.Errorf("Cache kann nicht geschrieben werden (%s)", err)
+	}
+	return resp, decodeBody(body, v)
+}
+
+func cachedResponse(req *http.Request, entry *CacheEntry, v interface{}) (*http.Response, error) {
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+	return resp, decodeBody([]byte(entry.Body), v)
+}
+
+func decodeBody(body []byte, v interface{}) error {
+	if v == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, v)
+}
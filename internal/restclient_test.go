@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -250,3 +251,287 @@ func TestRestClient_UpdateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestRestClient_SearchIssues(t *testing.T) {
+	tests := []struct {
+		testcase       string
+		query          url.Values
+		response       []byte
+		responseStatus int
+		result         *SearchResult
+		err            bool
+	}{
+		{
+			"search issues",
+			url.Values{"jql": {"project = DB"}, "fields": {"summary,status"}, "startAt": {"0"}, "maxResults": {"50"}},
+			[]byte("{\"startAt\": 0,\"maxResults\": 50,\"total\": 1,\"issues\": [{\"id\": \"10001\",\"key\": \"DB-1\",\"fields\": {\"summary\": \"Deploy\",\"status\": {\"name\": \"Done\"}}}]}"),
+			http.StatusOK,
+			&SearchResult{
+				StartAt:    0,
+				MaxResults: 50,
+				Total:      1,
+				Issues: []Issue{
+					{
+						Id:  "10001",
+						Key: "DB-1",
+						Fields: map[string]interface{}{
+							"summary": "Deploy",
+							"status":  map[string]interface{}{"name": "Done"},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"invalid jql",
+			url.Values{"jql": {"project = DB"}, "fields": {"summary,status"}, "startAt": {"0"}, "maxResults": {"50"}},
+			[]byte("{\"errorMessages\": [\"Error in the JQL Query\"]}"),
+			http.StatusBadRequest,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/rest/api/3/search" {
+					t.Errorf("got: %v - want: %v", req.URL.Path, "/rest/api/3/search")
+				}
+				if !reflect.DeepEqual(req.URL.Query(), tt.query) {
+					t.Errorf("got: %v - want: %v", req.URL.Query(), tt.query)
+				}
+				rw.WriteHeader(tt.responseStatus)
+				rw.Write(tt.response)
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL)
+			c, _ := CreateRestClient(nil, u)
+			res, err := c.SearchIssues("project = DB", []string{"summary", "status"}, 0, 50)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no Error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no Error - want: Error")
+			case err == nil && !reflect.DeepEqual(res, tt.result):
+				t.Errorf("got: %v - want: %v", res, tt.result)
+			}
+		})
+	}
+}
+
+func TestRestClient_GetVersionIssueCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.String() {
+		case "/rest/api/3/version/10000/relatedIssueCounts":
+			rw.Write([]byte("{\"self\": \"https://example.atlassian.net/rest/api/3/version/10000\",\"issuesFixedCount\": 23,\"issuesAffectedCount\": 101,\"issueCountWithCustomFieldsShowingVersion\": 54}"))
+		case "/rest/api/3/version/10000/unresolvedIssueCount":
+			rw.Write([]byte("{\"self\": \"https://example.atlassian.net/rest/api/3/version/10000\",\"issuesUnresolvedCount\": 7,\"issuesCount\": 30}"))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			rw.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c, _ := CreateRestClient(nil, u)
+	counts, err := c.GetVersionRelatedIssueCounts("10000")
+	if err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	} else if *counts != (VersionRelatedIssueCounts{IssuesFixedCount: 23, IssuesAffectedCount: 101}) {
+		t.Errorf("got: %v - want: %v", *counts, VersionRelatedIssueCounts{IssuesFixedCount: 23, IssuesAffectedCount: 101})
+	}
+	unresolved, err := c.GetVersionUnresolvedIssueCount("10000")
+	if err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	} else if *unresolved != (VersionUnresolvedIssueCount{IssuesUnresolvedCount: 7, IssuesCount: 30}) {
+		t.Errorf("got: %v - want: %v", *unresolved, VersionUnresolvedIssueCount{IssuesUnresolvedCount: 7, IssuesCount: 30})
+	}
+	_, err = c.GetVersionUnresolvedIssueCount("10001")
+	if err == nil {
+		t.Errorf("got: no Error - want: Error")
+	}
+}
+
+func TestRestClient_EditIssue(t *testing.T) {
+	tests := []struct {
+		testcase       string
+		responseStatus int
+		response       []byte
+		err            bool
+	}{
+		{
+			"can edit issue",
+			http.StatusNoContent,
+			nil,
+			false,
+		},
+		{
+			"cannot edit issue",
+			http.StatusBadRequest,
+			[]byte("{\"errorMessages\": [],\"errors\": {\"fixVersions\": \"Version name '2021-07' is not valid\"}}"),
+			true,
+		},
+		{
+			"issue not found",
+			http.StatusNotFound,
+			[]byte("{\"errorMessages\": [\"Issue does not exist or you do not have permission to see it.\"],\"errors\": {}}"),
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.Method != "PUT" || req.URL.String() != "/rest/api/3/issue/DB-1" {
+					t.Errorf("got: %v %v - want: PUT /rest/api/3/issue/DB-1", req.Method, req.URL.String())
+				}
+				update := IssueUpdate{}
+				_ = json.NewDecoder(req.Body).Decode(&update)
+				if len(update.Update["fixVersions"]) != 1 {
+					t.Errorf("got: %v - want: one fixVersions operation", update.Update)
+				}
+				rw.WriteHeader(tt.responseStatus)
+				rw.Write(tt.response)
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL)
+			c, _ := CreateRestClient(nil, u)
+			err := c.EditIssue("DB-1", IssueUpdate{Update: map[string][]FieldOperation{"fixVersions": {{Add: map[string]string{"name": "2021-07"}}}}})
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no Error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no Error - want: Error")
+			}
+		})
+	}
+}
+
+func TestRestClient_GetIssue(t *testing.T) {
+	tests := []struct {
+		testcase       string
+		response       []byte
+		responseStatus int
+		issue          *Issue
+		err            bool
+	}{
+		{
+			"get issue",
+			[]byte("{\"id\": \"10001\",\"key\": \"DB-1\",\"fields\": {\"summary\": \"Deploy\"}}"),
+			http.StatusOK,
+			&Issue{Id: "10001", Key: "DB-1", Fields: map[string]interface{}{"summary": "Deploy"}},
+			false,
+		},
+		{
+			"issue not found",
+			[]byte("{\"errorMessages\": [\"Issue does not exist or you do not have permission to see it.\"],\"errors\": {}}"),
+			http.StatusNotFound,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.String() != "/rest/api/3/issue/DB-1?fields=summary" {
+					t.Errorf("got: %v - want: %v", req.URL.String(), "/rest/api/3/issue/DB-1?fields=summary")
+				}
+				rw.WriteHeader(tt.responseStatus)
+				rw.Write(tt.response)
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL)
+			c, _ := CreateRestClient(nil, u)
+			issue, err := c.GetIssue("DB-1", []string{"summary"})
+			t2, ok := err.(RestError)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no Error", err)
+			case err != nil && (!ok || t2.Status() != tt.responseStatus):
+				t.Errorf("got: %v - want: %d", err, tt.responseStatus)
+			case err == nil && !reflect.DeepEqual(issue, tt.issue):
+				t.Errorf("got: %v - want: %v", issue, tt.issue)
+			}
+		})
+	}
+}
+
+func TestRestClient_Components(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method + " " + req.URL.String() {
+		case "GET /rest/api/3/project/DB/components":
+			rw.Write([]byte("[{\"id\": \"10000\",\"name\": \"backend\",\"leadAccountId\": \"5b10a\",\"assigneeType\": \"COMPONENT_LEAD\",\"project\": \"DB\",\"projectId\": 10000}]"))
+		case "POST /rest/api/3/component":
+			comp := Component{}
+			_ = json.NewDecoder(req.Body).Decode(&comp)
+			if comp.Name == "" {
+				rw.WriteHeader(http.StatusBadRequest)
+				rw.Write([]byte("{\"errors\": {\"name\": \"The component name specified is invalid\"}}"))
+				return
+			}
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte("{\"id\": \"10001\",\"name\": \"frontend\",\"project\": \"DB\",\"projectId\": 10000}"))
+		case "PUT /rest/api/3/component/10000":
+			rw.Write([]byte("{\"id\": \"10000\",\"name\": \"backend\",\"project\": \"DB\",\"projectId\": 10000}"))
+		case "DELETE /rest/api/3/component/10000?moveIssuesTo=10001":
+			rw.WriteHeader(http.StatusNoContent)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			rw.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c, _ := CreateRestClient(nil, u)
+	comps, err := c.GetComponents("DB")
+	want := []Component{{Id: "10000", Name: "backend", LeadAccountId: "5b10a", AssigneeType: "COMPONENT_LEAD", Project: "DB", ProjectId: 10000}}
+	if err != nil || !reflect.DeepEqual(comps, want) {
+		t.Errorf("got: %v %v - want: %v", comps, err, want)
+	}
+	if err := c.CreateComponent(Component{Name: "frontend", Project: "DB"}); err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	}
+	if err := c.CreateComponent(Component{Project: "DB"}); err == nil {
+		t.Errorf("got: no Error - want: Error")
+	}
+	if err := c.UpdateComponent(Component{Id: "10000", Name: "backend", AssigneeType: "PROJECT_LEAD"}); err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	}
+	if err := c.DeleteComponent("10000", "10001"); err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	}
+	if err := c.DeleteComponent("10000", ""); err == nil {
+		t.Errorf("got: no Error - want: Error")
+	}
+}
+
+func TestRestClient_MoveVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" || req.URL.String() != "/rest/api/3/version/10000/move" {
+			t.Errorf("got: %v %v - want: POST /rest/api/3/version/10000/move", req.Method, req.URL.String())
+		}
+		body := map[string]string{}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		if body["position"] != "First" {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("{\"errorMessages\": [\"Invalid position\"]}"))
+			return
+		}
+		rw.Write([]byte("{\"id\": \"10000\",\"name\": \"2021-07\"}"))
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c, _ := CreateRestClient(nil, u)
+	if err := c.MoveVersion("10000", "First"); err != nil {
+		t.Errorf("got: %v - want: no Error", err)
+	}
+	if err := c.MoveVersion("10000", "Top"); err == nil {
+		t.Errorf("got: no Error - want: Error")
+	}
+}
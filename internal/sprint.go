@@ -0,0 +1,284 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	SprintFuture = "future"
+	SprintActive = "active"
+	SprintClosed = "closed"
+
+	sprintTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+type Board struct {
+	Id       int           `json:"id"`
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Location BoardLocation `json:"location"`
+}
+
+type BoardLocation struct {
+	ProjectId  int    `json:"projectId"`
+	ProjectKey string `json:"projectKey"`
+}
+
+type Sprint struct {
+	Id            int     `json:"id,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	State         string  `json:"state,omitempty"`
+	StartDate     *string `json:"startDate,omitempty"`
+	EndDate       *string `json:"endDate,omitempty"`
+	CompleteDate  *string `json:"completeDate,omitempty"`
+	OriginBoardId int     `json:"originBoardId,omitempty"`
+	Goal          string  `json:"goal,omitempty"`
+}
+
+func (s Sprint) Start() (time.Time, bool) {
+	return sprintTime(s.StartDate)
+}
+
+func (s Sprint) End() (time.Time, bool) {
+	return sprintTime(s.EndDate)
+}
+
+type SprintChange struct {
+	Project string
+	Board   Board
+	Action  Operation
+	Sprint  Sprint
+	// Target receives the incomplete issues of a closed sprint
+	Target *Sprint
+	// Version creates a fixVersion named like the new sprint
+	Version bool
+}
+
+func (ch SprintChange) Change() Change {
+	change := Change{
+		Project:   ch.Project,
+		Object:    fmt.Sprintf("Sprint %s (Board %s)", ch.Sprint.Name, ch.Board.Name),
+		Operation: ch.Action,
+		State:     sprintPeriod(ch.Sprint),
+	}
+	if ch.Action == OpClose && ch.Target != nil {
+		change.State = fmt.Sprintf("offene Vorgänge nach Sprint %s", ch.Target.Name)
+	}
+	if ch.Version {
+		change.State += fmt.Sprintf(", fixVersion %s", ch.Sprint.Name)
+	}
+	return change
+}
+
+func (ch SprintChange) String() string {
+	return ch.Change().String()
+}
+
+// ScrumBoards returns the boards of a project that have sprints.
+func ScrumBoards(prjKey string, c RestClient) ([]Board, error) {
+	boards, err := c.GetBoards(prjKey)
+	if err != nil {
+		return nil, fmt.Errorf("Boards von Projekt %s können nicht gelesen werden (%s)", prjKey, err)
+	}
+	var scrum []Board
+	for _, b := range boards {
+		if b.Type == "scrum" {
+			scrum = append(scrum, b)
+		}
+	}
+	return scrum, nil
+}
+
+// PlanNextSprint starts the new sprint at the end of the latest sprint of the board, but not before today.
+func PlanNextSprint(prjKey string, board Board, sprints []Sprint, name string, length time.Duration, today time.Time) (*SprintChange, error) {
+	if _, err := findSprint(sprints, name); err == nil {
+		return nil, fmt.Errorf("Sprint %s ist in Board %s bereits vorhanden", name, board.Name)
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("Sprintlänge %s ist ungültig", length)
+	}
+	start := today
+	for _, s := range sprints {
+		if end, ok := s.End(); ok && end.After(start) {
+			start = end
+		}
+	}
+	sprint := Sprint{Name: name, OriginBoardId: board.Id}
+	sprint.StartDate = sprintTimeString(start)
+	sprint.EndDate = sprintTimeString(start.Add(length))
+	return &SprintChange{Project: prjKey, Board: board, Action: OpCreate, Sprint: sprint}, nil
+}
+
+// PlanStartSprint keeps planned dates of the sprint, missing dates start now.
+func PlanStartSprint(prjKey string, board Board, sprints []Sprint, name string, length time.Duration, now time.Time) (*SprintChange, error) {
+	sprint, err := findSprint(sprints, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s in Board %s", err, board.Name)
+	}
+	if sprint.State != SprintFuture {
+		return nil, fmt.Errorf("Sprint %s in Board %s ist nicht geplant (%s)", name, board.Name, sprint.State)
+	}
+	for _, s := range sprints {
+		if s.State == SprintActive {
+			return nil, fmt.Errorf("In Board %s läuft bereits Sprint %s", board.Name, s.Name)
+		}
+	}
+	start, ok := sprint.Start()
+	if !ok {
+		start = now
+		sprint.StartDate = sprintTimeString(start)
+	}
+	if _, ok := sprint.End(); !ok {
+		sprint.EndDate = sprintTimeString(start.Add(length))
+	}
+	sprint.State = SprintActive
+	return &SprintChange{Project: prjKey, Board: board, Action: OpStart, Sprint: *sprint}, nil
+}
+
+// PlanCloseSprint moves the incomplete issues to the next planned sprint of the board.
+func PlanCloseSprint(prjKey string, board Board, sprints []Sprint, name string) (*SprintChange, error) {
+	sprint, err := findSprint(sprints, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s in Board %s", err, board.Name)
+	}
+	if sprint.State != SprintActive {
+		return nil, fmt.Errorf("Sprint %s in Board %s läuft nicht (%s)", name, board.Name, sprint.State)
+	}
+	sprint.State = SprintClosed
+	ch := &SprintChange{Project: prjKey, Board: board, Action: OpClose, Sprint: *sprint}
+	var future []Sprint
+	for _, s := range sprints {
+		if s.State == SprintFuture {
+			future = append(future, s)
+		}
+	}
+	sort.SliceStable(future, func(i, j int) bool {
+		si, iok := future[i].Start()
+		sj, jok := future[j].Start()
+		if iok && jok {
+			return si.Before(sj)
+		}
+		return iok && !jok
+	})
+	if len(future) > 0 {
+		ch.Target = &future[0]
+	}
+	return ch, nil
+}
+
+func ApplySprintChange(ch SprintChange, c RestClient) error {
+	switch ch.Action {
+	case OpCreate:
+		if _, err := c.CreateSprint(ch.Sprint); err != nil {
+			return err
+		}
+		if ch.Version {
+			return ensureSprintVersion(ch.Project, ch.Sprint, c)
+		}
+		return nil
+	case OpClose:
+		jql := fmt.Sprintf("sprint = %d AND statusCategory != Done ORDER BY key", ch.Sprint.Id)
+		issues, err := SearchIssues(jql, []string{"summary"}, c)
+		if err != nil {
+			return err
+		}
+		if len(issues) > 0 && ch.Target == nil {
+			return fmt.Errorf("Sprint %s hat %d offene Vorgänge, aber Board %s hat keinen nächsten Sprint", ch.Sprint.Name, len(issues), ch.Board.Name)
+		}
+		if len(issues) > 0 {
+			var keys []string
+			for _, i := range issues {
+				keys = append(keys, i.Key)
+			}
+			if err := c.MoveIssuesToSprint(ch.Target.Id, keys); err != nil {
+				return err
+			}
+		}
+	}
+	return c.UpdateSprint(Sprint{Id: ch.Sprint.Id, State: ch.Sprint.State, StartDate: ch.Sprint.StartDate, EndDate: ch.Sprint.EndDate})
+}
+
+func ensureSprintVersion(prjKey string, sprint Sprint, c RestClient) error {
+	prj, err := c.GetProject(prjKey)
+	if err != nil {
+		return fmt.Errorf("Projekt %s kann nicht gelesen werden (%s)", prjKey, err)
+	}
+	if _, err := getVersion(prj, sprint.Name); err == nil {
+		return nil
+	}
+	prjId, err := strconv.Atoi(prj.Id)
+	if err != nil {
+		return fmt.Errorf("Projekt-Id %s ist ungültig", prj.Id)
+	}
+	ver := Version{Name: sprint.Name, ProjectId: prjId}
+	if start, ok := sprint.Start(); ok {
+		ver.StartDate = dateString(start)
+	}
+	if end, ok := sprint.End(); ok {
+		ver.ReleaseDate = dateString(end)
+	}
+	return c.CreateVersion(ver)
+}
+
+func SprintTable(boards []Board, sprints map[int][]Sprint) ([]string, [][]string) {
+	header := []string{"projekt", "board", "sprint", "status", "start", "ende"}
+	var rows [][]string
+	for _, b := range boards {
+		for _, s := range sprints[b.Id] {
+			if s.State == SprintClosed {
+				continue
+			}
+			start, end := "", ""
+			if t, ok := s.Start(); ok {
+				start = *dateString(t)
+			}
+			if t, ok := s.End(); ok {
+				end = *dateString(t)
+			}
+			rows = append(rows, []string{b.Location.ProjectKey, b.Name, s.Name, s.State, start, end})
+		}
+	}
+	return header, rows
+}
+
+func findSprint(sprints []Sprint, name string) (*Sprint, error) {
+	for _, s := range sprints {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("Sprint %s ist nicht vorhanden", name)
+}
+
+func sprintPeriod(s Sprint) string {
+	start, sok := s.Start()
+	end, eok := s.End()
+	if !sok || !eok {
+		return ""
+	}
+	return fmt.Sprintf("%s bis %s", *dateString(start), *dateString(end))
+}
+
+func sprintTime(s *string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(sprintTimeLayout, *s)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, *s)
+	}
+	return t, err == nil
+}
+
+func sprintTimeString(t time.Time) *string {
+	s := t.Format(sprintTimeLayout)
+	return &s
+}
+
+func dateString(t time.Time) *string {
+	s := t.Format("2006-01-02")
+	return &s
+}
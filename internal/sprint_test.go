@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanNextSprint(t *testing.T) {
+	board := Board{Id: 1, Name: "PRJ Board"}
+	today := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := "2021-03-08T09:00:00.000Z"
+	past := "2021-02-15T09:00:00.000Z"
+	tests := []struct {
+		testcase string
+		sprints  []Sprint
+		start    string
+		end      string
+		err      bool
+	}{
+		{"first sprint starts today", nil, "2021-03-01T00:00:00.000Z", "2021-03-15T00:00:00.000Z", false},
+		{"after latest sprint", []Sprint{{Name: "Sprint 1", EndDate: &past}, {Name: "Sprint 2", EndDate: &end}}, "2021-03-08T09:00:00.000Z", "2021-03-22T09:00:00.000Z", false},
+		{"not before today", []Sprint{{Name: "Sprint 1", EndDate: &past}}, "2021-03-01T00:00:00.000Z", "2021-03-15T00:00:00.000Z", false},
+		{"sprint already present", []Sprint{{Name: "Sprint 3"}}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			ch, err := PlanNextSprint("PRJ", board, tt.sprints, "Sprint 3", 14*24*time.Hour, today)
+			switch {
+			case err != nil && !tt.err:
+				t.Fatalf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Fatalf("got: no error - want: error")
+			case err != nil:
+				return
+			}
+			if *ch.Sprint.StartDate != tt.start || *ch.Sprint.EndDate != tt.end || ch.Sprint.OriginBoardId != 1 {
+				t.Errorf("got: %s - %s - want: %s - %s", *ch.Sprint.StartDate, *ch.Sprint.EndDate, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestPlanStartSprint(t *testing.T) {
+	board := Board{Id: 1, Name: "PRJ Board"}
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	start := "2021-03-02T09:00:00.000Z"
+	tests := []struct {
+		testcase string
+		sprints  []Sprint
+		start    string
+		end      string
+		err      bool
+	}{
+		{"without dates", []Sprint{{Id: 2, Name: "Sprint 2", State: SprintFuture}}, "2021-03-01T10:00:00.000Z", "2021-03-15T10:00:00.000Z", false},
+		{"planned start", []Sprint{{Id: 2, Name: "Sprint 2", State: SprintFuture, StartDate: &start}}, start, "2021-03-16T09:00:00.000Z", false},
+		{"other sprint active", []Sprint{{Id: 1, Name: "Sprint 1", State: SprintActive}, {Id: 2, Name: "Sprint 2", State: SprintFuture}}, "", "", true},
+		{"sprint closed", []Sprint{{Id: 2, Name: "Sprint 2", State: SprintClosed}}, "", "", true},
+		{"sprint missing", nil, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			ch, err := PlanStartSprint("PRJ", board, tt.sprints, "Sprint 2", 14*24*time.Hour, now)
+			switch {
+			case err != nil && !tt.err:
+				t.Fatalf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Fatalf("got: no error - want: error")
+			case err != nil:
+				return
+			}
+			if ch.Sprint.State != SprintActive || *ch.Sprint.StartDate != tt.start || *ch.Sprint.EndDate != tt.end {
+				t.Errorf("got: %+v - want: active from %s to %s", ch.Sprint, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestApplySprintChange_Close(t *testing.T) {
+	board := Board{Id: 1, Name: "PRJ Board"}
+	later := "2021-03-29T09:00:00.000Z"
+	earlier := "2021-03-15T09:00:00.000Z"
+	sprints := []Sprint{
+		{Id: 1, Name: "Sprint 1", State: SprintActive},
+		{Id: 3, Name: "Sprint 3", State: SprintFuture, StartDate: &later},
+		{Id: 2, Name: "Sprint 2", State: SprintFuture, StartDate: &earlier},
+	}
+	ch, err := PlanCloseSprint("PRJ", board, sprints, "Sprint 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Target == nil || ch.Target.Id != 2 {
+		t.Fatalf("got: %v - want: target Sprint 2", ch.Target)
+	}
+	if want := "Projekt PRJ: Sprint Sprint 1 (Board PRJ Board) abschließen (offene Vorgänge nach Sprint Sprint 2)"; ch.String() != want {
+		t.Errorf("got: %s - want: %s", ch, want)
+	}
+	c := &TestRestClient{issues: []Issue{{Key: "PRJ-1"}, {Key: "PRJ-2"}}}
+	if err := ApplySprintChange(*ch, c); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.moved, map[int][]string{2: {"PRJ-1", "PRJ-2"}}) {
+		t.Errorf("got: %v - want: PRJ-1, PRJ-2 moved to sprint 2", c.moved)
+	}
+	if len(c.sprintsOut) != 1 || c.sprintsOut[0].State != SprintClosed {
+		t.Errorf("got: %v - want: sprint closed", c.sprintsOut)
+	}
+
+	ch, _ = PlanCloseSprint("PRJ", board, sprints[:1], "Sprint 1")
+	if err := ApplySprintChange(*ch, &TestRestClient{issues: []Issue{{Key: "PRJ-1"}}}); err == nil {
+		t.Errorf("got: no error - want: error for open issues without next sprint")
+	}
+	if _, err := PlanCloseSprint("PRJ", board, sprints, "Sprint 2"); err == nil {
+		t.Errorf("got: no error - want: error for sprint not active")
+	}
+}
+
+func TestApplySprintChange_CreateVersion(t *testing.T) {
+	board := Board{Id: 1, Name: "PRJ Board"}
+	ch, err := PlanNextSprint("PRJ", board, nil, "2021-10", 14*24*time.Hour, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch.Version = true
+	c := &TestRestClient{}
+	if err := ApplySprintChange(*ch, c); err != nil {
+		t.Fatal(err)
+	}
+	startDate, releaseDate := "2021-03-01", "2021-03-15"
+	want := []Version{{Name: "2021-10", ProjectId: 10000, StartDate: &startDate, ReleaseDate: &releaseDate}}
+	if !reflect.DeepEqual(c.created, want) {
+		t.Errorf("got: %v - want: %v", c.created, want)
+	}
+	if len(c.sprintsOut) != 1 || c.sprintsOut[0].Name != "2021-10" {
+		t.Errorf("got: %v - want: sprint 2021-10 created", c.sprintsOut)
+	}
+
+	c = &TestRestClient{versions: []Version{{Id: "10001", Name: "2021-10"}}}
+	if err := ApplySprintChange(*ch, c); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.created) != 0 {
+		t.Errorf("got: %v - want: existing version kept", c.created)
+	}
+}
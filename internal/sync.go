@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type VersionChange struct {
+	Project *Project
+	Action  Operation
+	Version Version
+}
+
+func (ch VersionChange) Change() Change {
+	return Change{
+		Project:   ch.Project.Key,
+		Object:    fmt.Sprintf("Version %s", ch.Version.Name),
+		Operation: ch.Action,
+		State:     versionState(ch.Version),
+	}
+}
+
+func (ch VersionChange) String() string {
+	return ch.Change().String()
+}
+
+func PlanVersionSync(src, dst *Project, archiveExtra bool) ([]VersionChange, error) {
+	prjId, err := strconv.Atoi(dst.Id)
+	if err != nil {
+		return nil, fmt.Errorf("Projekt-Id %s ist ungültig", dst.Id)
+	}
+	var changes []VersionChange
+	for _, sv := range src.Versions {
+		dv, err := getVersion(dst, sv.Name)
+		if err != nil {
+			ver := Version{
+				Name:        sv.Name,
+				Archived:    sv.Archived,
+				Released:    sv.Released,
+				ReleaseDate: sv.ReleaseDate,
+				StartDate:   sv.StartDate,
+				ProjectId:   prjId,
+			}
+			changes = append(changes, VersionChange{Project: dst, Action: OpCreate, Version: ver})
+			continue
+		}
+		// a start date is only taken over, StartDate is omitted when empty and cannot be cleared
+		sameStart := sv.StartDate == nil || equalDate(dv.StartDate, sv.StartDate)
+		if dv.Archived == sv.Archived && dv.Released == sv.Released && equalDate(dv.ReleaseDate, sv.ReleaseDate) && sameStart {
+			continue
+		}
+		action := OpUpdate
+		switch {
+		case sv.Archived && !dv.Archived:
+			action = OpArchive
+		case sv.Released && !dv.Released:
+			action = OpRelease
+		}
+		dv.Archived = sv.Archived
+		dv.Released = sv.Released
+		dv.ReleaseDate = sv.ReleaseDate
+		if sv.StartDate != nil {
+			dv.StartDate = sv.StartDate
+		}
+		dv.UserReleaseDate = nil
+		changes = append(changes, VersionChange{Project: dst, Action: action, Version: *dv})
+	}
+	if !archiveExtra {
+		return changes, nil
+	}
+	for _, dv := range dst.Versions {
+		if _, err := getVersion(src, dv.Name); err == nil || dv.Archived {
+			continue
+		}
+		dv.Archived = true
+		dv.UserReleaseDate = nil
+		changes = append(changes, VersionChange{Project: dst, Action: OpArchive, Version: dv})
+	}
+	return changes, nil
+}
+
+func ApplyVersionChange(ch VersionChange, c RestClient) error {
+	if ch.Action == OpCreate {
+		return c.CreateVersion(ch.Version)
+	}
+	return c.UpdateVersion(ch.Version)
+}
+
+func versionState(ver Version) string {
+	var state string
+	switch {
+	case ver.Archived && ver.Released:
+		state = "archiviert, released"
+	case ver.Archived:
+		state = "archiviert"
+	case ver.Released:
+		state = "released"
+	default:
+		state = "nicht released"
+	}
+	if ver.ReleaseDate != nil {
+		state += fmt.Sprintf(", Release Datum %s", *ver.ReleaseDate)
+	}
+	return state
+}
+
+func equalDate(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
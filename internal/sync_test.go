@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanVersionSync(t *testing.T) {
+	relDate := "2021-07-06"
+	otherDate := "2021-07-09"
+	startDate := "2021-06-01"
+	src := Project{
+		Id:  "10000",
+		Key: "RELEASE",
+		Versions: []Version{
+			{Id: "10001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 10000},
+			{Id: "10002", Name: "2021-08", ProjectId: 10000},
+		},
+	}
+	tests := []struct {
+		testcase     string
+		project      Project
+		archiveExtra bool
+		changes      []VersionChange
+		err          bool
+	}{
+		{
+			"project in sync",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", ProjectId: 20000},
+				},
+			},
+			false,
+			nil,
+			false,
+		},
+		{
+			"missing versions",
+			Project{Id: "20000", Key: "DB"},
+			false,
+			[]VersionChange{
+				{Action: OpCreate, Version: Version{Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000}},
+				{Action: OpCreate, Version: Version{Name: "2021-08", ProjectId: 20000}},
+			},
+			false,
+		},
+		{
+			"different state",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20001", Name: "2021-07", ReleaseDate: &otherDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", ProjectId: 20000},
+				},
+			},
+			false,
+			[]VersionChange{
+				{Action: OpRelease, Version: Version{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000}},
+			},
+			false,
+		},
+		{
+			"different release date",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &otherDate, StartDate: &startDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", ProjectId: 20000},
+				},
+			},
+			false,
+			[]VersionChange{
+				{Action: OpUpdate, Version: Version{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000}},
+			},
+			false,
+		},
+		{
+			"start date only in target",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", StartDate: &startDate, ProjectId: 20000},
+				},
+			},
+			false,
+			nil,
+			false,
+		},
+		{
+			"extra versions kept",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20000", Name: "2021-06", ProjectId: 20000},
+					{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", ProjectId: 20000},
+				},
+			},
+			false,
+			nil,
+			false,
+		},
+		{
+			"extra versions archived",
+			Project{
+				Id:  "20000",
+				Key: "DB",
+				Versions: []Version{
+					{Id: "20000", Name: "2021-06", ProjectId: 20000},
+					{Id: "19999", Name: "2021-05", Archived: true, ProjectId: 20000},
+					{Id: "20001", Name: "2021-07", Released: true, ReleaseDate: &relDate, StartDate: &startDate, ProjectId: 20000},
+					{Id: "20002", Name: "2021-08", ProjectId: 20000},
+				},
+			},
+			true,
+			[]VersionChange{
+				{Action: OpArchive, Version: Version{Id: "20000", Name: "2021-06", Archived: true, ProjectId: 20000}},
+			},
+			false,
+		},
+		{
+			"invalid project-id",
+			Project{Id: "CHAR_ID", Key: "DB"},
+			false,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			changes, err := PlanVersionSync(&src, &tt.project, tt.archiveExtra)
+			for i := range tt.changes {
+				tt.changes[i].Project = &tt.project
+			}
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case !reflect.DeepEqual(changes, tt.changes):
+				t.Errorf("got: %v - want: %v", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestApplyVersionChange(t *testing.T) {
+	prj := &Project{Id: "10000", Key: "DB"}
+	c := &TestRestClient{}
+	_ = ApplyVersionChange(VersionChange{Project: prj, Action: OpCreate, Version: Version{Name: "2021-07"}}, c)
+	_ = ApplyVersionChange(VersionChange{Project: prj, Action: OpArchive, Version: Version{Id: "10001", Name: "2021-06", Archived: true}}, c)
+	if !reflect.DeepEqual(c.created, []Version{{Name: "2021-07"}}) {
+		t.Errorf("got: %v - want: %v", c.created, []Version{{Name: "2021-07"}})
+	}
+	if !reflect.DeepEqual(c.updated, []Version{{Id: "10001", Name: "2021-06", Archived: true}}) {
+		t.Errorf("got: %v - want: %v", c.updated, []Version{{Id: "10001", Name: "2021-06", Archived: true}})
+	}
+}
+
+func TestVersionChange_String(t *testing.T) {
+	relDate := "2021-07-06"
+	ch := VersionChange{
+		Project: &Project{Key: "DB"},
+		Action:  OpCreate,
+		Version: Version{Name: "2021-07", Released: true, ReleaseDate: &relDate},
+	}
+	want := "Projekt DB: Version 2021-07 anlegen (released, Release Datum 2021-07-06)"
+	if ch.String() != want {
+		t.Errorf("got: %s - want: %s", ch.String(), want)
+	}
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultSubTaskType = "Sub-task"
+
+// IssueTemplate describes the issues created for a version. Summary, description,
+// labels, components and fixVersion may contain the placeholders {project},
+// {version} and {releaseDate}, the description is written in Markdown. The placeholders
+// of the description are filled after parsing, their values are not read as Markdown.
+type IssueTemplate struct {
+	Issues []TemplateIssue `json:"issues"`
+}
+
+type TemplateIssue struct {
+	IssueType   string   `json:"issueType"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	Components  []string `json:"components"`
+	// FixVersion defaults to the version the issues are created for
+	FixVersion string          `json:"fixVersion"`
+	SubTasks   []TemplateIssue `json:"subTasks"`
+}
+
+type TemplateValues struct {
+	Project     string
+	Version     string
+	ReleaseDate string
+}
+
+func LoadIssueTemplate(path string) (*IssueTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Vorlage %s kann nicht gelesen werden (%s)", path, err)
+	}
+	t := &IssueTemplate{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("Vorlage %s ist ungültig (%s)", path, err)
+	}
+	if len(t.Issues) == 0 {
+		return nil, fmt.Errorf("Vorlage %s enthält keine Vorgänge", path)
+	}
+	for _, ti := range t.Issues {
+		if err := ti.validate(); err != nil {
+			return nil, fmt.Errorf("Vorlage %s ist ungültig (%s)", path, err)
+		}
+	}
+	return t, nil
+}
+
+func (ti TemplateIssue) validate() error {
+	if strings.TrimSpace(ti.Summary) == "" {
+		return fmt.Errorf("Vorgang ohne summary")
+	}
+	if strings.TrimSpace(ti.IssueType) == "" {
+		return fmt.Errorf("Vorgang %s ohne issueType", ti.Summary)
+	}
+	for _, st := range ti.SubTasks {
+		if len(st.SubTasks) > 0 {
+			return fmt.Errorf("Unteraufgabe %s kann keine Unteraufgaben haben", st.Summary)
+		}
+		if strings.TrimSpace(st.Summary) == "" {
+			return fmt.Errorf("Unteraufgabe von %s ohne summary", ti.Summary)
+		}
+	}
+	return nil
+}
+
+// CreateTemplateIssues creates the issues of the template with their sub-tasks in the
+// project and returns the keys of the created issues. It stops at the first error.
+func CreateTemplateIssues(prj *Project, t *IssueTemplate, values TemplateValues, c RestClient) ([]string, error) {
+	values.Project = prj.Key
+	r := values.replacer()
+	var keys []string
+	for _, ti := range t.Issues {
+		issue, err := c.CreateIssue(templateFields(prj, ti, r, values.Version, nil))
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, issue.Key)
+		for _, st := range ti.SubTasks {
+			if st.IssueType == "" {
+				st.IssueType = defaultSubTaskType
+			}
+			if st.FixVersion == "" {
+				st.FixVersion = ti.FixVersion
+			}
+			subTask, err := c.CreateIssue(templateFields(prj, st, r, values.Version, issue))
+			if err != nil {
+				return keys, err
+			}
+			keys = append(keys, subTask.Key)
+		}
+	}
+	return keys, nil
+}
+
+func templateFields(prj *Project, ti TemplateIssue, r *strings.Replacer, verName string, parent *Issue) IssueUpdate {
+	fixVersion := verName
+	if ti.FixVersion != "" {
+		fixVersion = r.Replace(ti.FixVersion)
+	}
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": prj.Key},
+		"issuetype":   map[string]string{"name": ti.IssueType},
+		"summary":     r.Replace(ti.Summary),
+		"fixVersions": []map[string]string{{"name": fixVersion}},
+	}
+	if ti.Description != "" {
+		doc := adf.ReplaceText(*adf.FromMarkdown(ti.Description), r)
+		fields["description"] = &doc
+	}
+	if len(ti.Labels) > 0 {
+		var labels []string
+		for _, l := range ti.Labels {
+			labels = append(labels, r.Replace(l))
+		}
+		fields["labels"] = labels
+	}
+	if len(ti.Components) > 0 {
+		var comps []map[string]string
+		for _, comp := range ti.Components {
+			comps = append(comps, map[string]string{"name": r.Replace(comp)})
+		}
+		fields["components"] = comps
+	}
+	if parent != nil {
+		fields["parent"] = map[string]string{"key": parent.Key}
+	}
+	return IssueUpdate{Fields: fields}
+}
+
+func (v TemplateValues) replacer() *strings.Replacer {
+	return strings.NewReplacer("{project}", v.Project, "{version}", v.Version, "{releaseDate}", v.ReleaseDate)
+}
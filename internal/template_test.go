@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"bitbucket.org/christian_m/jiratool/internal/adf"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreateTemplateIssues(t *testing.T) {
+	template, err := LoadIssueTemplate("testdata/release-template.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &TestRestClient{}
+	keys, err := CreateTemplateIssues(&Project{Id: "10000", Key: "PRJ"}, template, TemplateValues{Version: "1.2.0", ReleaseDate: "2021-06-01"}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"PRJ-101", "PRJ-102", "PRJ-103", "PRJ-104"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got: %v - want: %v", keys, want)
+	}
+	tests := []struct {
+		field string
+		want  []interface{}
+	}{
+		{"summary", []interface{}{"Deploy PRJ 1.2.0", "Smoke Test 1.2.0", "Changelog 1.2.0", "Release Notes PRJ 1.2.0"}},
+		{"issuetype", []interface{}{
+			map[string]string{"name": "Task"}, map[string]string{"name": "Sub-task"},
+			map[string]string{"name": "Subtask"}, map[string]string{"name": "Task"},
+		}},
+		{"fixVersions", []interface{}{
+			[]map[string]string{{"name": "1.2.0"}}, []map[string]string{{"name": "1.2.0"}},
+			[]map[string]string{{"name": "1.2.0"}}, []map[string]string{{"name": "Doku"}},
+		}},
+		{"labels", []interface{}{[]string{"release", "release-1.2.0"}, nil, []string{"docs"}, nil}},
+		{"parent", []interface{}{nil, map[string]string{"key": "PRJ-101"}, map[string]string{"key": "PRJ-101"}, nil}},
+	}
+	for _, tt := range tests {
+		for i, issue := range c.issuesOut {
+			if got := issue.Fields[tt.field]; !reflect.DeepEqual(got, tt.want[i]) {
+				t.Errorf("got: %s %v - want: %v", tt.field, got, tt.want[i])
+			}
+		}
+	}
+	doc, ok := c.issuesOut[0].Fields["description"].(*adf.Node)
+	want := "Deployment von **1.2.0** am 2021-06-01.\n\n- Rollback vorbereiten\n- [Runbook](https://example.com/runbook) lesen"
+	if !ok || adf.ToMarkdown(doc) != want {
+		t.Errorf("got: %v - want: document of %q", c.issuesOut[0].Fields["description"], want)
+	}
+
+	c = &TestRestClient{}
+	if _, err := CreateTemplateIssues(&Project{Id: "10000", Key: "PRJ"}, template, TemplateValues{Version: "*2021_07*", ReleaseDate: "2021-06-01"}, c); err != nil {
+		t.Fatal(err)
+	}
+	doc, _ = c.issuesOut[0].Fields["description"].(*adf.Node)
+	if want := "Deployment von *2021_07* am 2021-06-01."; doc == nil || !strings.HasPrefix(adf.ToText(doc), want) {
+		t.Errorf("got: %v - want: document starting with %q", c.issuesOut[0].Fields["description"], want)
+	}
+}
+
+func TestLoadIssueTemplate_Invalid(t *testing.T) {
+	for _, path := range []string{"testdata/missing.json", "testdata/cassettes/project.json"} {
+		if _, err := LoadIssueTemplate(path); err == nil {
+			t.Errorf("got: no error - want: error for %s", path)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const traceBodyLimit = 2000
+
+var traceMaskedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Tracer is a http.RoundTripper that logs every request and response of a
+// JiraRestClient. Credentials in the URL and headers are masked.
+type Tracer struct {
+	Transport http.RoundTripper
+	Out       io.Writer
+
+	mu sync.Mutex
+}
+
+func (t *Tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	u := *req.URL
+	u.User = nil
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "--> %s %s\n", req.Method, u.String())
+	writeTraceHeader(&sb, req.Header)
+	writeTraceBody(&sb, body, 0)
+	start := time.Now()
+	resp, err := t.Transport.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		_, _ = fmt.Fprintf(&sb, "<-- %s %s: %s (%s)\n", req.Method, u.String(), err, elapsed)
+		t.write(sb.String())
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(&sb, "<-- %s (%s)\n", resp.Status, elapsed)
+	writeTraceHeader(&sb, resp.Header)
+	writeTraceBody(&sb, respBody, traceBodyLimit)
+	t.write(sb.String())
+	return resp, nil
+}
+
+func (t *Tracer) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.Out, s)
+}
+
+func writeTraceHeader(sb *strings.Builder, header http.Header) {
+	var names []string
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if containsFold(traceMaskedHeaders, name) {
+			value = maskSecret(value)
+		}
+		_, _ = fmt.Fprintf(sb, "    %s: %s\n", name, value)
+	}
+}
+
+func writeTraceBody(sb *strings.Builder, body []byte, limit int) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return
+	}
+	if limit > 0 && len(body) > limit {
+		_, _ = fmt.Fprintf(sb, "    %s... (%d Bytes gekürzt)\n", body[:limit], len(body)-limit)
+		return
+	}
+	_, _ = fmt.Fprintf(sb, "    %s\n", body)
+}
+
+// maskSecret keeps the authentication scheme, e.g. "Basic ***".
+func maskSecret(value string) string {
+	if i := strings.IndexByte(value, ' '); i > 0 {
+		return value[:i] + " ***"
+	}
+	return "***"
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Set-Cookie", "atlassian.xsrf.token=secret")
+		if req.Method == http.MethodPut {
+			_, _ = rw.Write([]byte(`{"id":"10101","description":"` + strings.Repeat("x", 3000) + `"}`))
+			return
+		}
+		_, _ = rw.Write([]byte(`{"id":"10000","key":"PRJ","versions":[]}`))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	c, _ := CreateRestClient(url.UserPassword("username", "apikey"), u)
+	out := &strings.Builder{}
+	c.HttpClient = &http.Client{Transport: &Tracer{Transport: http.DefaultTransport, Out: out}}
+
+	if _, err := c.GetProject("PRJ"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateVersion(Version{Id: "10101", Name: "1.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	trace := out.String()
+	for _, want := range []string{
+		"--> GET " + ts.URL + "/rest/api/3/project/PRJ\n",
+		"    Authorization: Basic ***\n",
+		"<-- 200 OK (",
+		`    {"id":"10000","key":"PRJ","versions":[]}`,
+		"--> PUT " + ts.URL + "/rest/api/3/version/10101\n",
+		`    {"id":"10101","name":"1.1.0",`,
+		"    Set-Cookie: ***\n",
+		"... (1031 Bytes gekürzt)\n",
+	} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("got: %s - want: %q in trace", trace, want)
+		}
+	}
+	for _, secret := range []string{"username", "apikey", "xsrf", "dXNlcm5hbWU6YXBpa2V5"} {
+		if strings.Contains(trace, secret) {
+			t.Errorf("got: %s in trace - want: masked", secret)
+		}
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Basic dXNlcm5hbWU6YXBpa2V5", "Basic ***"},
+		{"Bearer token", "Bearer ***"},
+		{"token", "***"},
+	}
+	for _, tt := range tests {
+		if got := maskSecret(tt.value); got != tt.want {
+			t.Errorf("got: %s - want: %s", got, tt.want)
+		}
+	}
+}
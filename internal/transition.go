@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Transition struct {
+	Id     string                     `json:"id"`
+	Name   string                     `json:"name"`
+	To     TransitionStatus           `json:"to"`
+	Fields map[string]TransitionField `json:"fields"`
+}
+
+type TransitionStatus struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TransitionField struct {
+	Required        bool                     `json:"required"`
+	Name            string                   `json:"name"`
+	HasDefaultValue bool                     `json:"hasDefaultValue"`
+	AllowedValues   []map[string]interface{} `json:"allowedValues"`
+}
+
+type TransitionResult struct {
+	Key string
+	// Skipped is set for issues that are already in the target status
+	Skipped bool
+	Err     error
+}
+
+// TransitionPlan is the transition leading an issue to the target status with the values
+// of its required fields.
+type TransitionPlan struct {
+	Key        string
+	Transition Transition
+	Fields     map[string]interface{}
+	// Skipped is set for issues that are already in the target status
+	Skipped bool
+	Err     error
+}
+
+func (p TransitionPlan) Change() Change {
+	return Change{
+		Project:   IssueProjectKey(p.Key),
+		Object:    "Vorgang " + p.Key,
+		Operation: OpTransition,
+		State:     fmt.Sprintf("%s über %s", p.Transition.To.Name, p.Transition.Name),
+	}
+}
+
+// PlanTransitions finds the transitions of the issues to the status. Required fields without
+// default are taken from values by field id or name.
+func PlanTransitions(issueKeys []string, status string, values map[string]string, c RestClient) []TransitionPlan {
+	var plans []TransitionPlan
+	for _, k := range issueKeys {
+		plans = append(plans, planTransition(k, status, values, c))
+	}
+	return plans
+}
+
+// ApplyTransitions moves the issues to the status by the planned transitions.
+func ApplyTransitions(plans []TransitionPlan, c RestClient) []TransitionResult {
+	var results []TransitionResult
+	for _, p := range plans {
+		err := p.Err
+		if err == nil && !p.Skipped {
+			err = c.TransitionIssue(p.Key, p.Transition.Id, p.Fields)
+		}
+		results = append(results, TransitionResult{Key: p.Key, Skipped: p.Skipped, Err: err})
+	}
+	return results
+}
+
+func planTransition(issueKey, status string, values map[string]string, c RestClient) TransitionPlan {
+	plan := TransitionPlan{Key: issueKey}
+	transitions, err := c.GetTransitions(issueKey)
+	if err != nil {
+		plan.Err = err
+		return plan
+	}
+	var names []string
+	for _, tr := range transitions {
+		if !strings.EqualFold(tr.To.Name, status) {
+			names = append(names, tr.To.Name)
+			continue
+		}
+		plan.Transition = tr
+		plan.Fields, plan.Err = transitionFields(tr, values)
+		return plan
+	}
+	issue, err := c.GetIssue(issueKey, []string{"status"})
+	if err == nil && strings.EqualFold(issue.Field("status"), status) {
+		plan.Skipped = true
+		return plan
+	}
+	plan.Err = fmt.Errorf("Status %s ist nicht erreichbar (möglich: %s)", status, strings.Join(names, ", "))
+	return plan
+}
+
+func transitionFields(tr Transition, values map[string]string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for id, f := range tr.Fields {
+		value, ok := values[id]
+		if !ok {
+			value, ok = values[f.Name]
+		}
+		switch {
+		case ok && len(f.AllowedValues) > 0:
+			allowed, err := allowedValue(f, value)
+			if err != nil {
+				return nil, err
+			}
+			fields[id] = allowed
+		case ok:
+			fields[id] = value
+		case !f.Required || f.HasDefaultValue:
+		case len(f.AllowedValues) > 0:
+			return nil, fmt.Errorf("Pflichtfeld %s (%s) für Übergang %s fehlt (möglich: %s)", f.Name, id, tr.Name, strings.Join(allowedNames(f), ", "))
+		default:
+			return nil, fmt.Errorf("Pflichtfeld %s (%s) für Übergang %s fehlt", f.Name, id, tr.Name)
+		}
+	}
+	return fields, nil
+}
+
+func allowedValue(f TransitionField, value string) (map[string]interface{}, error) {
+	for _, av := range f.AllowedValues {
+		for _, k := range []string{"name", "value", "id"} {
+			if s, ok := av[k].(string); ok && strings.EqualFold(s, value) {
+				return map[string]interface{}{"id": av["id"]}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Wert %s für Feld %s ist ungültig (möglich: %s)", value, f.Name, strings.Join(allowedNames(f), ", "))
+}
+
+func allowedNames(f TransitionField) []string {
+	var names []string
+	for _, av := range f.AllowedValues {
+		names = append(names, fieldString(av))
+	}
+	return names
+}
+
+// ParseFieldValues reads "field=value" pairs separated by commas.
+func ParseFieldValues(list string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("Feldwert %s ist ungültig (Feld=Wert)", pair)
+		}
+		values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return values, nil
+}
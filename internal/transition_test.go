@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var errAny = errors.New("any error")
+
+func TestApplyTransitions(t *testing.T) {
+	resolution := TransitionField{
+		Required:      true,
+		Name:          "Resolution",
+		AllowedValues: []map[string]interface{}{{"id": "10000", "name": "Done"}, {"id": "10001", "name": "Won't Do"}},
+	}
+	c := &TestRestClient{
+		issues: []Issue{{Key: "PRJ-4", Fields: map[string]interface{}{"status": map[string]interface{}{"name": "Done"}}}},
+		transitions: map[string][]Transition{
+			"PRJ-1": {
+				{Id: "21", Name: "Reopen", To: TransitionStatus{Name: "In Progress"}},
+				{Id: "41", Name: "Release", To: TransitionStatus{Name: "Done"}, Fields: map[string]TransitionField{"resolution": resolution}},
+			},
+			"PRJ-2": {{Id: "11", Name: "Start Progress", To: TransitionStatus{Name: "In Progress"}}},
+			"PRJ-3": {{Id: "41", Name: "Release", To: TransitionStatus{Name: "Done"}, Fields: map[string]TransitionField{
+				"customfield_10020": {Required: true, Name: "Release Notes"},
+			}}},
+			"PRJ-4": {{Id: "51", Name: "Reopen", To: TransitionStatus{Name: "To Do"}}},
+		},
+	}
+	tests := []struct {
+		testcase string
+		values   map[string]string
+		results  []TransitionResult
+		fields   map[string]interface{}
+	}{
+		{
+			"required fields missing",
+			nil,
+			[]TransitionResult{{Key: "PRJ-1", Err: errAny}, {Key: "PRJ-2", Err: errAny}, {Key: "PRJ-3", Err: errAny}, {Key: "PRJ-4", Skipped: true}},
+			nil,
+		},
+		{
+			"required fields from values",
+			map[string]string{"Resolution": "won't do", "customfield_10020": "keine"},
+			[]TransitionResult{{Key: "PRJ-1"}, {Key: "PRJ-2", Err: errAny}, {Key: "PRJ-3"}, {Key: "PRJ-4", Skipped: true}},
+			map[string]interface{}{"resolution": map[string]interface{}{"id": "10001"}},
+		},
+		{
+			"invalid allowed value",
+			map[string]string{"resolution": "Fixed"},
+			[]TransitionResult{{Key: "PRJ-1", Err: errAny}, {Key: "PRJ-2", Err: errAny}, {Key: "PRJ-3", Err: errAny}, {Key: "PRJ-4", Skipped: true}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c.transited = nil
+			results := ApplyTransitions(PlanTransitions([]string{"PRJ-1", "PRJ-2", "PRJ-3", "PRJ-4"}, "done", tt.values, c), c)
+			for i, r := range results {
+				want := tt.results[i]
+				if r.Key != want.Key || r.Skipped != want.Skipped || (r.Err != nil) != (want.Err != nil) {
+					t.Errorf("got: %+v - want: %+v", r, want)
+				}
+			}
+			if fields, ok := c.transited["PRJ-1 41"]; ok && !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("got: %v - want: %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestPlanTransitions(t *testing.T) {
+	c := &TestRestClient{transitions: map[string][]Transition{
+		"PRJ-1": {{Id: "41", Name: "Release", To: TransitionStatus{Name: "Done"}, Fields: map[string]TransitionField{"resolution": {
+			Required:      true,
+			Name:          "Resolution",
+			AllowedValues: []map[string]interface{}{{"id": "10000", "name": "Done"}, {"id": "10001", "name": "Won't Do"}},
+		}}}},
+	}}
+	plans := PlanTransitions([]string{"PRJ-1"}, "Done", nil, c)
+	if plans[0].Err == nil || !strings.Contains(plans[0].Err.Error(), "möglich: Done, Won't Do") {
+		t.Errorf("got: %v - want: missing resolution with allowed values", plans[0].Err)
+	}
+	plans = PlanTransitions([]string{"PRJ-1"}, "Done", map[string]string{"resolution": "Done"}, c)
+	want := Change{Project: "PRJ", Object: "Vorgang PRJ-1", Operation: OpTransition, State: "Done über Release"}
+	if plans[0].Err != nil || plans[0].Change() != want || !Destructive([]Change{want}) {
+		t.Errorf("got: %v %v - want: %v", plans[0].Err, plans[0].Change(), want)
+	}
+	if c.transited != nil {
+		t.Errorf("got: %v - want: nothing transitioned by planning", c.transited)
+	}
+}
+
+func TestParseFieldValues(t *testing.T) {
+	values, err := ParseFieldValues("resolution=Done, customfield_10020 = a=b,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"resolution": "Done", "customfield_10020": "a=b"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got: %v - want: %v", values, want)
+	}
+	if _, err := ParseFieldValues("resolution"); err == nil {
+		t.Errorf("got: no error - want: error for missing value")
+	}
+}
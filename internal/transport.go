@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+type TransportConfig struct {
+	Proxy      string `json:"proxy"`
+	CACert     string `json:"caCert"`
+	ClientCert string `json:"clientCert"`
+	ClientKey  string `json:"clientKey"`
+}
+
+func (tc TransportConfig) IsDefault() bool {
+	return tc == TransportConfig{}
+}
+
+// HttpClient builds the client for JiraRestClient. Without settings http.DefaultClient
+// is used, the proxy then still follows HTTPS_PROXY and NO_PROXY.
+func (tc TransportConfig) HttpClient() (*http.Client, error) {
+	if tc.IsDefault() {
+		return http.DefaultClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tc.Proxy != "" {
+		u, err := url.Parse(tc.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("Proxy %s ist ungültig", tc.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tc.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(tc.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("CA Zertifikate %s können nicht gelesen werden (%s)", tc.CACert, err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA Zertifikate %s enthalten kein PEM Zertifikat", tc.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if tc.ClientCert != "" || tc.ClientKey != "" {
+		if tc.ClientCert == "" || tc.ClientKey == "" {
+			return nil, fmt.Errorf("Bitte Client-Zertifikat und Schlüssel angeben")
+		}
+		cert, err := tls.LoadX509KeyPair(tc.ClientCert, tc.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Client-Zertifikat %s kann nicht gelesen werden (%s)", tc.ClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestCertificate(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jiratool"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestTransportConfig_HttpClient(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeTestCertificate(t, dir)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("{}"))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+	defer ts.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("kein Zertifikat"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testcase string
+		config   TransportConfig
+		err      bool
+		callErr  bool
+	}{
+		{"ca and client certificate", TransportConfig{CACert: caFile, ClientCert: certFile, ClientKey: keyFile}, false, false},
+		{"missing client certificate", TransportConfig{CACert: caFile}, false, true},
+		{"unknown ca", TransportConfig{ClientCert: certFile, ClientKey: keyFile}, false, true},
+		{"invalid ca file", TransportConfig{CACert: invalidFile}, true, false},
+		{"missing ca file", TransportConfig{CACert: filepath.Join(dir, "missing.pem")}, true, false},
+		{"client certificate without key", TransportConfig{ClientCert: certFile}, true, false},
+		{"invalid proxy", TransportConfig{Proxy: "::"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c, err := tt.config.HttpClient()
+			switch {
+			case err != nil && !tt.err:
+				t.Fatalf("got: %v - want: no Error", err)
+			case err == nil && tt.err:
+				t.Fatalf("got: no Error - want: Error")
+			case err != nil:
+				return
+			}
+			resp, err := c.Get(ts.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if (err != nil) != tt.callErr {
+				t.Errorf("got: %v - want error: %v", err, tt.callErr)
+			}
+		})
+	}
+}
+
+func TestTransportConfig_Proxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		proxied = req.URL.Host == "jira.example.com"
+		_, _ = rw.Write([]byte("{}"))
+	}))
+	defer proxy.Close()
+	c, err := TransportConfig{Proxy: proxy.URL}.HttpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Get("http://jira.example.com/rest/api/3/myself")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if !proxied {
+		t.Errorf("got: request not sent through proxy - want: proxied")
+	}
+
+	c, err = TransportConfig{}.HttpClient()
+	if err != nil || c != http.DefaultClient {
+		t.Errorf("got: %v, %v - want: http.DefaultClient", c, err)
+	}
+}
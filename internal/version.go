@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type Version struct {
@@ -10,11 +11,49 @@ type Version struct {
 	Name            string  `json:"name"`
 	Archived        bool    `json:"archived"`
 	Released        bool    `json:"released"`
+	StartDate       *string `json:"startDate,omitempty"`
 	ReleaseDate     *string `json:"releaseDate"`
 	UserReleaseDate *string `json:"userReleaseDate"`
 	ProjectId       int     `json:"projectId"`
 }
 
+var versionPositions = []string{"First", "Earlier", "Later", "Last"}
+
+type VersionRelatedIssueCounts struct {
+	IssuesFixedCount    int `json:"issuesFixedCount"`
+	IssuesAffectedCount int `json:"issuesAffectedCount"`
+}
+
+type VersionUnresolvedIssueCount struct {
+	IssuesUnresolvedCount int `json:"issuesUnresolvedCount"`
+	IssuesCount           int `json:"issuesCount"`
+}
+
+type ReleaseCheck struct {
+	Project          *Project
+	Version          *Version
+	IssuesFixed      int
+	IssuesAffected   int
+	IssuesUnresolved int
+	UnresolvedIssues []Issue
+}
+
+func (r *ReleaseCheck) Ready() bool {
+	return r.IssuesUnresolved == 0
+}
+
+func (r *ReleaseCheck) String() string {
+	if r.Ready() {
+		return fmt.Sprintf("Version %s in Projekt %s hat keine offenen Vorgänge (%d erledigt)", r.Version.Name, r.Project.Key, r.IssuesFixed)
+	}
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Version %s in Projekt %s hat %d offene Vorgänge:", r.Version.Name, r.Project.Key, r.IssuesUnresolved)
+	for _, i := range r.UnresolvedIssues {
+		_, _ = fmt.Fprintf(&sb, "\n  %s %s (%s)", i.Key, i.Field("summary"), i.Field("status"))
+	}
+	return sb.String()
+}
+
 func InspectVersion(prj *Project, verName string, c RestClient) (string, error) {
 	ver, err := getVersion(prj, verName)
 	if err != nil {
@@ -33,6 +72,10 @@ func InspectVersion(prj *Project, verName string, c RestClient) (string, error)
 	return verData, nil
 }
 
+func VersionSummary(ver Version) string {
+	return fmt.Sprintf("%s (%s)", ver.Name, versionCell(ver))
+}
+
 func CreateVersion(prj *Project, verName string, c RestClient) error {
 	prjId, err := strconv.Atoi(prj.Id)
 	if err != nil {
@@ -48,6 +91,34 @@ func CreateVersion(prj *Project, verName string, c RestClient) error {
 	return err
 }
 
+func CheckRelease(prj *Project, relVer string, c RestClient) (*ReleaseCheck, error) {
+	ver, err := getVersion(prj, relVer)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.GetVersionRelatedIssueCounts(ver.Id)
+	if err != nil {
+		return nil, err
+	}
+	unresolved, err := c.GetVersionUnresolvedIssueCount(ver.Id)
+	if err != nil {
+		return nil, err
+	}
+	check := &ReleaseCheck{
+		Project:          prj,
+		Version:          ver,
+		IssuesFixed:      counts.IssuesFixedCount,
+		IssuesAffected:   counts.IssuesAffectedCount,
+		IssuesUnresolved: unresolved.IssuesUnresolvedCount,
+	}
+	if check.Ready() {
+		return check, nil
+	}
+	jql := fmt.Sprintf("fixVersion = %s AND resolution = Unresolved ORDER BY key", ver.Id)
+	check.UnresolvedIssues, err = SearchIssues(jql, []string{"summary", "status"}, c)
+	return check, err
+}
+
 func ReleaseVersion(prj *Project, relVer, relDate string, c RestClient) error {
 	ver, err2 := getVersion(prj, relVer)
 	if err2 != nil {
@@ -61,6 +132,90 @@ func ReleaseVersion(prj *Project, relVer, relDate string, c RestClient) error {
 	return err
 }
 
+func ArchiveVersion(prj *Project, verName string, c RestClient) error {
+	ver, err := getVersion(prj, verName)
+	if err != nil {
+		return err
+	}
+	if ver.Archived {
+		return fmt.Errorf("Version %s in Projekt %s ist bereits archiviert", verName, prj.Key)
+	}
+	ver.Archived = true
+	ver.UserReleaseDate = nil
+	return c.UpdateVersion(*ver)
+}
+
+func MoveVersion(prj *Project, verName, position string, c RestClient) error {
+	ver, err := getVersion(prj, verName)
+	if err != nil {
+		return err
+	}
+	for _, p := range versionPositions {
+		if strings.EqualFold(p, position) {
+			return c.MoveVersion(ver.Id, p)
+		}
+	}
+	return fmt.Errorf("Position %s ist ungültig (%s)", position, strings.Join(versionPositions, ", "))
+}
+
+func AssignFixVersion(issueKeys []string, verName string, replace bool, c RestClient) []IssueResult {
+	var prjKeys []string
+	prjIssues := make(map[string][]string)
+	for _, k := range issueKeys {
+		pk := IssueProjectKey(k)
+		if _, ok := prjIssues[pk]; !ok {
+			prjKeys = append(prjKeys, pk)
+		}
+		prjIssues[pk] = append(prjIssues[pk], k)
+	}
+	update := fixVersionUpdate(verName, replace)
+	var results []IssueResult
+	for _, pk := range prjKeys {
+		err := ensureVersion(pk, verName, c)
+		for _, k := range prjIssues[pk] {
+			if err != nil {
+				results = append(results, IssueResult{Key: k, Err: err})
+				continue
+			}
+			results = append(results, IssueResult{Key: k, Err: c.EditIssue(k, update)})
+		}
+	}
+	return results
+}
+
+func ensureVersion(prjKey, verName string, c RestClient) error {
+	prj, err := c.GetProject(prjKey)
+	if err != nil {
+		return fmt.Errorf("Projekt %s kann nicht gelesen werden (%s)", prjKey, err)
+	}
+	if _, err := getVersion(prj, verName); err == nil {
+		return nil
+	}
+	return CreateVersion(prj, verName, c)
+}
+
+func fixVersionUpdate(verName string, replace bool) IssueUpdate {
+	ver := map[string]string{"name": verName}
+	op := FieldOperation{Add: ver}
+	if replace {
+		op = FieldOperation{Set: []interface{}{ver}}
+	}
+	return IssueUpdate{Update: map[string][]FieldOperation{"fixVersions": {op}}}
+}
+
+// VersionIssues returns the issues with the version as fixVersion, jql restricts them further.
+func VersionIssues(prj *Project, verName, jql string, fields []string, c RestClient) ([]Issue, error) {
+	ver, err := getVersion(prj, verName)
+	if err != nil {
+		return nil, err
+	}
+	versionJQL := fmt.Sprintf("fixVersion = %s", ver.Id)
+	if strings.TrimSpace(jql) != "" {
+		versionJQL += fmt.Sprintf(" AND (%s)", jql)
+	}
+	return SearchIssues(versionJQL+" ORDER BY key", fields, c)
+}
+
 func getVersion(prj *Project, relVer string) (*Version, error) {
 	var ver *Version = nil
 	for _, v := range prj.Versions {
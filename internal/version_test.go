@@ -1,6 +1,12 @@
 package internal
 
-import "testing"
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
 
 func TestInspectVersion(t *testing.T) {
 	testReleaseDate := "2021-04-01"
@@ -210,20 +216,351 @@ func TestReleaseVersion(t *testing.T) {
 	}
 }
 
-type TestRestClient struct{}
+func TestCheckRelease(t *testing.T) {
+	prj := Project{
+		Key: "PRJ",
+		Versions: []Version{
+			{
+				Id:        "10001",
+				Name:      "2021-02",
+				ProjectId: 10000,
+			},
+		},
+	}
+	tests := []struct {
+		testcase    string
+		versionName string
+		issues      []Issue
+		ready       bool
+		checkData   string
+		err         bool
+	}{
+		{
+			"version without unresolved issues",
+			"2021-02",
+			nil,
+			true,
+			"Version 2021-02 in Projekt PRJ hat keine offenen Vorgänge (0 erledigt)",
+			false,
+		},
+		{
+			"version with unresolved issues",
+			"2021-02",
+			[]Issue{
+				{Key: "PRJ-1", Fields: map[string]interface{}{"summary": "Blocker", "status": map[string]interface{}{"name": "In Progress"}}},
+				{Key: "PRJ-2", Fields: map[string]interface{}{"summary": "Smoke Test", "status": map[string]interface{}{"name": "To Do"}}},
+			},
+			false,
+			"Version 2021-02 in Projekt PRJ hat 2 offene Vorgänge:\n  PRJ-1 Blocker (In Progress)\n  PRJ-2 Smoke Test (To Do)",
+			false,
+		},
+		{
+			"version in project not present",
+			"2021-03",
+			nil,
+			false,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			check, err := CheckRelease(&prj, tt.versionName, &TestRestClient{issues: tt.issues})
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case err == nil && check.Ready() != tt.ready:
+				t.Errorf("got: %v - want: %v", check.Ready(), tt.ready)
+			case err == nil && check.String() != tt.checkData:
+				t.Errorf("got: %s - want: %s", check.String(), tt.checkData)
+			}
+		})
+	}
+}
+
+type TestRestClient struct {
+	versions    []Version
+	components  []Component
+	issues      []Issue
+	created     []Version
+	updated     []Version
+	edited      map[string]IssueUpdate
+	issuesOut   []IssueUpdate
+	comments    map[string][]Comment
+	linkTypes   []IssueLinkType
+	links       []IssueLink
+	boards      []Board
+	sprints     map[int][]Sprint
+	moved       map[int][]string
+	sprintsOut  []Sprint
+	transitions map[string][]Transition
+	transited   map[string]map[string]interface{}
+}
 
 func (c *TestRestClient) GetProject(prjKey string) (*Project, error) {
-	return nil, nil
+	return &Project{Id: "10000", Key: prjKey, Versions: c.versions}, nil
 }
 
 func (c *TestRestClient) CreateVersion(version Version) error {
+	c.created = append(c.created, version)
 	return nil
 }
 
 func (c *TestRestClient) UpdateVersion(version Version) error {
+	c.updated = append(c.updated, version)
+	return nil
+}
+
+func (c *TestRestClient) DeleteVersion(version Version) error {
+	var versions []Version
+	for _, v := range c.versions {
+		if v.Id != version.Id {
+			versions = append(versions, v)
+		}
+	}
+	c.versions = versions
+	return nil
+}
+
+func (c *TestRestClient) MoveVersion(verId, position string) error {
+	return nil
+}
+
+func (c *TestRestClient) GetComponents(prjKey string) ([]Component, error) {
+	return c.components, nil
+}
+
+func (c *TestRestClient) CreateComponent(component Component) error {
+	c.components = append(c.components, component)
+	return nil
+}
+
+func (c *TestRestClient) UpdateComponent(component Component) error {
+	for i, comp := range c.components {
+		if comp.Id == component.Id {
+			c.components[i] = component
+		}
+	}
+	return nil
+}
+
+func (c *TestRestClient) DeleteComponent(compId, moveIssuesTo string) error {
+	var comps []Component
+	for _, comp := range c.components {
+		if comp.Id != compId {
+			comps = append(comps, comp)
+		}
+	}
+	c.components = comps
+	return nil
+}
+
+func (c *TestRestClient) GetVersionRelatedIssueCounts(verId string) (*VersionRelatedIssueCounts, error) {
+	return &VersionRelatedIssueCounts{}, nil
+}
+
+func (c *TestRestClient) GetVersionUnresolvedIssueCount(verId string) (*VersionUnresolvedIssueCount, error) {
+	return &VersionUnresolvedIssueCount{IssuesUnresolvedCount: len(c.issues), IssuesCount: len(c.issues)}, nil
+}
+
+func (c *TestRestClient) SearchIssues(jql string, fields []string, startAt, maxResults int) (*SearchResult, error) {
+	return &SearchResult{Total: len(c.issues), Issues: c.issues}, nil
+}
+
+func (c *TestRestClient) GetIssue(issueKey string, fields []string) (*Issue, error) {
+	for _, i := range c.issues {
+		if i.Key == issueKey {
+			return &i, nil
+		}
+	}
+	return nil, RestError{"404 Not Found", http.StatusNotFound}
+}
+
+func (c *TestRestClient) EditIssue(issueKey string, update IssueUpdate) error {
+	if c.edited == nil {
+		c.edited = make(map[string]IssueUpdate)
+	}
+	c.edited[issueKey] = update
 	return nil
 }
 
+func (c *TestRestClient) CreateIssue(issue IssueUpdate) (*Issue, error) {
+	c.issuesOut = append(c.issuesOut, issue)
+	n := len(c.issuesOut)
+	return &Issue{Id: strconv.Itoa(20000 + n), Key: fmt.Sprintf("PRJ-%d", 100+n)}, nil
+}
+
+func (c *TestRestClient) AddComment(issueKey string, comment Comment) error {
+	if c.comments == nil {
+		c.comments = make(map[string][]Comment)
+	}
+	c.comments[issueKey] = append(c.comments[issueKey], comment)
+	return nil
+}
+
+func (c *TestRestClient) GetIssueLinkTypes() ([]IssueLinkType, error) {
+	return c.linkTypes, nil
+}
+
+func (c *TestRestClient) CreateIssueLink(link IssueLink) error {
+	c.links = append(c.links, link)
+	return nil
+}
+
+func (c *TestRestClient) GetTransitions(issueKey string) ([]Transition, error) {
+	return c.transitions[issueKey], nil
+}
+
+func (c *TestRestClient) TransitionIssue(issueKey, transitionId string, fields map[string]interface{}) error {
+	if c.transited == nil {
+		c.transited = make(map[string]map[string]interface{})
+	}
+	c.transited[issueKey+" "+transitionId] = fields
+	return nil
+}
+
+func (c *TestRestClient) GetBoards(prjKey string) ([]Board, error) {
+	return c.boards, nil
+}
+
+func (c *TestRestClient) GetSprints(boardId int) ([]Sprint, error) {
+	return c.sprints[boardId], nil
+}
+
+func (c *TestRestClient) CreateSprint(sprint Sprint) (*Sprint, error) {
+	c.sprintsOut = append(c.sprintsOut, sprint)
+	return &sprint, nil
+}
+
+func (c *TestRestClient) UpdateSprint(sprint Sprint) error {
+	c.sprintsOut = append(c.sprintsOut, sprint)
+	return nil
+}
+
+func (c *TestRestClient) MoveIssuesToSprint(sprintId int, issueKeys []string) error {
+	if c.moved == nil {
+		c.moved = make(map[int][]string)
+	}
+	c.moved[sprintId] = append(c.moved[sprintId], issueKeys...)
+	return nil
+}
+
+func TestArchiveVersion(t *testing.T) {
+	prj := Project{
+		Key: "PRJ",
+		Versions: []Version{
+			{Id: "10000", Name: "2021-01", Archived: true, ProjectId: 10000},
+			{Id: "10001", Name: "2021-02", ProjectId: 10000},
+		},
+	}
+	tests := []struct {
+		testcase    string
+		versionName string
+		updated     []Version
+		err         bool
+	}{
+		{"archive version", "2021-02", []Version{{Id: "10001", Name: "2021-02", Archived: true, ProjectId: 10000}}, false},
+		{"version already archived", "2021-01", nil, true},
+		{"version in project not present", "2021-03", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &TestRestClient{}
+			err := ArchiveVersion(&prj, tt.versionName, c)
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			case !reflect.DeepEqual(c.updated, tt.updated):
+				t.Errorf("got: %v - want: %v", c.updated, tt.updated)
+			}
+		})
+	}
+}
+
+func TestMoveVersion(t *testing.T) {
+	prj := Project{
+		Key:      "PRJ",
+		Versions: []Version{{Id: "10001", Name: "2021-02", ProjectId: 10000}},
+	}
+	tests := []struct {
+		testcase    string
+		versionName string
+		position    string
+		err         bool
+	}{
+		{"move version", "2021-02", "first", false},
+		{"invalid position", "2021-02", "top", true},
+		{"version in project not present", "2021-03", "Last", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			err := MoveVersion(&prj, tt.versionName, tt.position, &TestRestClient{})
+			switch {
+			case err != nil && !tt.err:
+				t.Errorf("got: %v - want: no error", err)
+			case err == nil && tt.err:
+				t.Errorf("got: no error - want: error")
+			}
+		})
+	}
+}
+
+func TestAssignFixVersion(t *testing.T) {
+	tests := []struct {
+		testcase    string
+		versions    []Version
+		issueKeys   []string
+		versionName string
+		replace     bool
+		created     []Version
+		operation   FieldOperation
+	}{
+		{
+			"add existing version",
+			[]Version{{Id: "10001", Name: "2021-07", ProjectId: 10000}},
+			[]string{"DB-1", "DB-2"},
+			"2021-07",
+			false,
+			nil,
+			FieldOperation{Add: map[string]string{"name": "2021-07"}},
+		},
+		{
+			"set missing version",
+			nil,
+			[]string{"DB-1", "MN-2"},
+			"2021-07",
+			true,
+			[]Version{{Name: "2021-07", ProjectId: 10000}, {Name: "2021-07", ProjectId: 10000}},
+			FieldOperation{Set: []interface{}{map[string]string{"name": "2021-07"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testcase, func(t *testing.T) {
+			c := &TestRestClient{versions: tt.versions}
+			results := AssignFixVersion(tt.issueKeys, tt.versionName, tt.replace, c)
+			if len(results) != len(tt.issueKeys) {
+				t.Errorf("got: %d - want: %d", len(results), len(tt.issueKeys))
+			}
+			for _, r := range results {
+				if r.Err != nil {
+					t.Errorf("got: %v - want: no error", r.Err)
+				}
+				update := c.edited[r.Key]
+				if !reflect.DeepEqual(update.Update["fixVersions"], []FieldOperation{tt.operation}) {
+					t.Errorf("got: %v - want: %v", update.Update["fixVersions"], tt.operation)
+				}
+			}
+			if !reflect.DeepEqual(c.created, tt.created) {
+				t.Errorf("got: %v - want: %v", c.created, tt.created)
+			}
+		})
+	}
+}
+
 func TestGetVersion(t *testing.T) {
 	tests := []struct {
 		testcase    string